@@ -0,0 +1,66 @@
+package main
+
+import (
+	"Lab1/internal/app/catalog"
+	"Lab1/internal/app/dsn"
+	"Lab1/internal/app/repository"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	_ "github.com/lib/pq"
+)
+
+// Импорт каталога звёзд из локального файла:
+//
+//	go run ./cmd/import -file bsc5.dat -format bsc -dry-run
+func main() {
+	file := flag.String("file", "", "путь к файлу каталога")
+	format := flag.String("format", catalog.FormatCSV, "формат каталога: csv, bsc, hipparcos")
+	dryRun := flag.Bool("dry-run", false, "только показать изменения, не записывая их в БД")
+	flag.Parse()
+
+	if *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	connStr := dsn.FromEnv()
+	if connStr == "" {
+		connStr = "host=127.0.0.1 user=alex password=password123 dbname=RIP port=5432 sslmode=disable"
+	}
+
+	repo, err := repository.NewRepository(connStr)
+	if err != nil {
+		log.Fatalf("Ошибка подключения к БД: %v", err)
+	}
+	if err := repo.Migrate(); err != nil {
+		log.Fatalf("Ошибка миграции БД: %v", err)
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatalf("Ошибка открытия файла: %v", err)
+	}
+	defer f.Close()
+
+	rows, rowErrors, err := catalog.Parse(*format, f)
+	if err != nil {
+		log.Fatalf("Ошибка разбора каталога: %v", err)
+	}
+
+	report, err := catalog.Import(repo, rows, rowErrors, *dryRun)
+	if err != nil {
+		log.Fatalf("Ошибка импорта: %v", err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("Создано: %d, обновлено: %d, без изменений: %d, ошибок: %d",
+		report.Created, report.Updated, report.Unchanged, len(report.Errors))
+}
@@ -26,6 +26,10 @@ func main() {
 		log.Fatalf("Ошибка подключения к БД: %v", err)
 	}
 
+	if err := repo.Migrate(); err != nil {
+		log.Fatalf("Ошибка миграции БД: %v", err)
+	}
+
 	config.InitMinio()
 
 	h := handler.NewHandler(repo)
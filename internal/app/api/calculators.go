@@ -0,0 +1,189 @@
+package api
+
+import (
+	"Lab1/internal/app/astro"
+	"Lab1/internal/app/calculator"
+	"Lab1/internal/app/models"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func InitCalculatorAPI(database *gorm.DB, r *gin.RouterGroup) {
+	db = database
+	registerCalculatorRoutes(r)
+}
+
+func registerCalculatorRoutes(r *gin.RouterGroup) {
+	calculators := r.Group("/calculators")
+	{
+		calculators.GET("", getCalculators)
+	}
+
+	r.POST("/calculate", calculate)
+	r.POST("/exposure", calculateExposure)
+}
+
+// Список доступных калькуляторов результата
+func getCalculators(c *gin.Context) {
+	list := []gin.H{}
+	for _, calc := range calculator.All() {
+		list = append(list, gin.H{
+			"name":    calc.Name(),
+			"version": calc.Version(),
+			"unit":    calc.Unit(),
+			"ucd":     calc.UCD(),
+			"default": calc.Name() == calculator.Default,
+		})
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// POST /api/calculate
+// Body JSON: { "star_ids":[1,2], "coordinates":[{"ra":83.63,"dec":22.01}],
+//
+//	"latitude":55.75, "longitude":37.62, "time":"2025-01-15T20:00:00Z", "calculator":"altitude" }
+//
+// Вместо latitude/longitude можно передать "site_id" места наблюдения из справочника,
+// "telescope_id" задаёт телескоп для калькуляторов, которым нужны параметры инструмента.
+//
+// Предварительный расчёт без создания заявки
+func calculate(c *gin.Context) {
+	var req struct {
+		StarIDs     []int `json:"star_ids"`
+		Coordinates []struct {
+			RA  float64 `json:"ra"`
+			Dec float64 `json:"dec"`
+		} `json:"coordinates"`
+		Latitude    *float64   `json:"latitude"`
+		Longitude   *float64   `json:"longitude"`
+		SiteID      *int       `json:"site_id"`
+		TelescopeID *int       `json:"telescope_id"`
+		Time        *time.Time `json:"time"`
+		Calculator  string     `json:"calculator"`
+	}
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный JSON: " + err.Error()})
+		return
+	}
+
+	if len(req.StarIDs) == 0 && len(req.Coordinates) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Нужны star_ids или coordinates"})
+		return
+	}
+
+	// Место наблюдения задаётся либо site_id, либо обеими координатами
+	hasCoords := req.Latitude != nil || req.Longitude != nil
+	switch {
+	case req.SiteID != nil && hasCoords:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Нельзя одновременно задать site_id и координаты наблюдателя"})
+		return
+	case req.SiteID == nil && (req.Latitude == nil || req.Longitude == nil):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Нужен site_id или latitude и longitude"})
+		return
+	case hasCoords && (*req.Latitude < -90 || *req.Latitude > 90 || *req.Longitude < -180 || *req.Longitude > 180):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректные координаты места наблюдения"})
+		return
+	}
+
+	// Заявка собирается только в памяти и в БД не сохраняется
+	observedAt := time.Now()
+	if req.Time != nil {
+		observedAt = *req.Time
+	}
+	order := models.TelescopeObservation{
+		ObservationDate: &observedAt,
+		Calculator:      req.Calculator,
+	}
+	if hasCoords {
+		order.ObserverLatitude, order.ObserverLongitude = *req.Latitude, *req.Longitude
+	}
+	if req.SiteID != nil {
+		site, err := repo.GetSiteByID(*req.SiteID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Место наблюдения не найдено"})
+			return
+		}
+		order.SetSite(site)
+	}
+	if req.TelescopeID != nil {
+		telescope, err := repo.GetTelescopeByID(*req.TelescopeID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Телескоп не найден"})
+			return
+		}
+		order.TelescopeID = &telescope.TelescopeID
+		order.Telescope = telescope
+	}
+
+	calc, err := calculator.ForOrder(&order)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var stars []models.Star
+	if len(req.StarIDs) > 0 {
+		stars, err = repo.GetStarsByIDs(req.StarIDs)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка получения звёзд: " + err.Error()})
+			return
+		}
+		if len(stars) != len(uniqueIDs(req.StarIDs)) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Некоторые звёзды не найдены"})
+			return
+		}
+	}
+	for i, coord := range req.Coordinates {
+		if coord.RA < 0 || coord.RA >= 360 || coord.Dec < -90 || coord.Dec > 90 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Некорректные координаты #%d", i+1)})
+			return
+		}
+		stars = append(stars, models.Star{RA: coord.RA, Dec: coord.Dec, Epoch: astro.DefaultEpoch, Frame: astro.FrameICRS})
+	}
+
+	observer := order.Observer()
+	results := make([]gin.H, 0, len(stars))
+	for _, star := range stars {
+		ra, dec := star.ApparentPosition(observedAt)
+		hor := astro.EquatorialToHorizontal(ra, dec, observer, observedAt)
+		vis := astro.RiseTransitSet(ra, dec, observer, observedAt)
+		res := calc.Calculate(&order, star)
+
+		item := gin.H{
+			"ra":                  star.RA,
+			"dec":                 star.Dec,
+			"altitude":            hor.Altitude,
+			"azimuth":             hor.Azimuth,
+			"hour_angle":          hor.HourAngle,
+			"local_sidereal_time": hor.LocalSiderealTime,
+			"airmass":             res.Airmass,
+			"extinction":          res.Extinction,
+			"visibility":          vis,
+			"result_value":        res.Value,
+		}
+		if star.StarID != 0 {
+			item["star_id"] = star.StarID
+			item["star_name"] = star.StarName
+		}
+		results = append(results, item)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"calculator":         calc.Name(),
+		"calculator_version": calc.Version(),
+		"observation_date":   observedAt,
+		"results":            results,
+	})
+}
+
+func uniqueIDs(ids []int) map[int]bool {
+	set := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
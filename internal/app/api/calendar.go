@@ -0,0 +1,121 @@
+package api
+
+import (
+	"Lab1/internal/app/astro"
+	"Lab1/internal/app/models"
+	"Lab1/internal/app/repository"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func InitCalendarAPI(database *gorm.DB, r *gin.RouterGroup) {
+	db = database
+	repo = repository.NewRepositoryFromDB(db)
+	registerCalendarRoutes(r)
+}
+
+func registerCalendarRoutes(r *gin.RouterGroup) {
+	calendar := r.Group("/calendar")
+	{
+		calendar.GET("/dark-time", getDarkTime)
+	}
+}
+
+// GET /api/calendar/dark-time?site=1&month=2025-01
+// Сумерки, восход и заход Луны и тёмное время на каждую ночь месяца для места наблюдения.
+// Без site берётся место по умолчанию, без month — текущий месяц по времени места.
+func getDarkTime(c *gin.Context) {
+	site, ok := siteFromQuery(c)
+	if !ok {
+		return
+	}
+	loc := site.Location()
+
+	month := time.Now().In(loc)
+	if text := c.Query("month"); text != "" {
+		parsed, err := time.Parse("2006-01", text)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Параметр month должен быть в формате ГГГГ-ММ"})
+			return
+		}
+		month = parsed
+	}
+
+	observer := site.Observer()
+	first := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	nights := []gin.H{}
+	for day := first; day.Month() == first.Month(); day = day.AddDate(0, 0, 1) {
+		start := astro.LocalNoon(observer, day.Year(), day.Month(), day.Day())
+		dark := astro.DarkTimeFrom(observer, start).In(loc)
+
+		intervals := []gin.H{}
+		var best *astro.Interval
+		for i, interval := range dark.DarkIntervals {
+			intervals = append(intervals, gin.H{"start": interval.Start, "end": interval.End})
+			if best == nil || interval.Duration() > best.Duration() {
+				best = &dark.DarkIntervals[i]
+			}
+		}
+
+		night := gin.H{
+			"date":              day.Format("2006-01-02"),
+			"sunset":            dark.Sunset,
+			"astronomical_dusk": dark.AstronomicalDusk,
+			"astronomical_dawn": dark.AstronomicalDawn,
+			"sunrise":           dark.Sunrise,
+			"moon_rise":         dark.MoonRise,
+			"moon_set":          dark.MoonSet,
+			"moon_illumination": math.Round(dark.MoonIllumination*100) / 100,
+			"moon_phase":        dark.MoonPhase,
+			"dark_intervals":    intervals,
+			"dark_hours":        math.Round(dark.DarkHours*100) / 100,
+		}
+		// Начало самого длинного тёмного промежутка — подсказка для даты наблюдения
+		if best != nil {
+			night["suggested_observation_date"] = best.Start
+		}
+		nights = append(nights, night)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"site":     site,
+		"month":    first.Format("2006-01"),
+		"timezone": loc.String(),
+		"nights":   nights,
+	})
+}
+
+// siteFromQuery возвращает место наблюдения из параметра ?site=, а без него — место по умолчанию.
+// При ошибке ответ уже записан и возвращается ok = false.
+func siteFromQuery(c *gin.Context) (*models.ObservingSite, bool) {
+	text := c.Query("site")
+	if text == "" {
+		site, err := repo.GetDefaultSite()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка получения места наблюдения: " + err.Error()})
+			return nil, false
+		}
+		if site == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Нужен параметр site: место по умолчанию не задано"})
+			return nil, false
+		}
+		return site, true
+	}
+
+	id, err := strconv.Atoi(text)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный параметр site"})
+		return nil, false
+	}
+	site, err := repo.GetSiteByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Место наблюдения не найдено"})
+		return nil, false
+	}
+	return site, true
+}
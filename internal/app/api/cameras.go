@@ -0,0 +1,175 @@
+package api
+
+import (
+	"Lab1/internal/app/models"
+	"Lab1/internal/app/repository"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func InitCameraAPI(database *gorm.DB, r *gin.RouterGroup) {
+	db = database
+	repo = repository.NewRepositoryFromDB(db)
+	registerCameraRoutes(r)
+}
+
+func registerCameraRoutes(r *gin.RouterGroup) {
+	cameras := r.Group("/cameras")
+	{
+		cameras.GET("", getCameras)
+		cameras.GET("/:id", getCameraByID)
+		cameras.POST("", createCamera)
+		cameras.PUT("/:id", updateCamera)
+		cameras.DELETE("/:id", deleteCamera)
+	}
+}
+
+// validateCamera проверяет параметры камеры
+func validateCamera(cam *models.Camera) error {
+	cam.Name = strings.TrimSpace(cam.Name)
+	if cam.Name == "" {
+		return errors.New("Название камеры обязательно")
+	}
+	if cam.QuantumEfficiency <= 0 || cam.QuantumEfficiency > 1 {
+		return errors.New("Квантовая эффективность должна быть в интервале (0, 1]")
+	}
+	if cam.PixelSize <= 0 {
+		return errors.New("Размер пикселя должен быть положительным, мкм")
+	}
+	if cam.ReadNoise < 0 || cam.DarkCurrent < 0 || cam.ReadoutTime < 0 {
+		return errors.New("Шум считывания, темновой ток и время считывания не могут быть отрицательными")
+	}
+	if cam.Width < 0 || cam.Height < 0 {
+		return errors.New("Размер кадра не может быть отрицательным")
+	}
+	return nil
+}
+
+func getCameras(c *gin.Context) {
+	cameras, err := repo.GetCameras()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка получения камер: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, cameras)
+}
+
+func getCameraByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный ID"})
+		return
+	}
+
+	camera, err := repo.GetCameraByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Камера не найдена"})
+		return
+	}
+	c.JSON(http.StatusOK, camera)
+}
+
+func createCamera(c *gin.Context) {
+	var input models.Camera
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный JSON: " + err.Error()})
+		return
+	}
+	input.CameraID = 0
+
+	if err := validateCamera(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := repo.CreateCamera(&input); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка сохранения в БД: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Камера добавлена",
+		"camera":  input,
+	})
+}
+
+func updateCamera(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный ID"})
+		return
+	}
+
+	if _, err := repo.GetCameraByID(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Камера не найдена"})
+		return
+	}
+
+	var input models.Camera
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный JSON: " + err.Error()})
+		return
+	}
+
+	if err := validateCamera(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = repo.UpdateCameraFields(id, map[string]interface{}{
+		"name":               input.Name,
+		"quantum_efficiency": input.QuantumEfficiency,
+		"read_noise":         input.ReadNoise,
+		"dark_current":       input.DarkCurrent,
+		"pixel_size":         input.PixelSize,
+		"width":              input.Width,
+		"height":             input.Height,
+		"readout_time":       input.ReadoutTime,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при обновлении: " + err.Error()})
+		return
+	}
+
+	camera, _ := repo.GetCameraByID(id)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Камера обновлена",
+		"camera":  camera,
+	})
+}
+
+func deleteCamera(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный ID"})
+		return
+	}
+
+	if _, err := repo.GetCameraByID(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Камера не найдена"})
+		return
+	}
+
+	count, err := repo.CountCameraOrders(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка проверки заявок: " + err.Error()})
+		return
+	}
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Камера используется в заявках: %d", count)})
+		return
+	}
+
+	if err := repo.DeleteCamera(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при удалении камеры"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Камера удалена"})
+}
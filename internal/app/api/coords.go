@@ -0,0 +1,103 @@
+package api
+
+import (
+	"Lab1/internal/app/astro"
+	"Lab1/internal/app/models"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Форматы вывода координат звёзд, выбираются параметром ?coords=
+const (
+	coordsDecimal     = "decimal"
+	coordsSexagesimal = "sexagesimal"
+)
+
+// starInput — тело запроса создания/изменения звезды.
+// RA и Dec принимаются числом градусов или строкой вида "05h34m31.94s" / "+22°00′52.2″".
+type starInput struct {
+	models.Star
+	RA  json.RawMessage `json:"RA"`
+	Dec json.RawMessage `json:"Dec"`
+}
+
+// sexagesimalStar — звезда с координатами в шестидесятеричной записи
+type sexagesimalStar struct {
+	models.Star
+	RA  string `json:"RA"`
+	Dec string `json:"Dec"`
+}
+
+// bindStar читает звезду из JSON запроса, разбирая и проверяя координаты
+func bindStar(c *gin.Context) (models.Star, error) {
+	var input starInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		return models.Star{}, fmt.Errorf("Некорректный JSON: %w", err)
+	}
+
+	star := input.Star
+	if len(input.RA) > 0 {
+		ra, err := parseCoordinate(input.RA, astro.ParseRA)
+		if err != nil {
+			return models.Star{}, err
+		}
+		star.RA = ra
+	}
+	if len(input.Dec) > 0 {
+		dec, err := parseCoordinate(input.Dec, astro.ParseDec)
+		if err != nil {
+			return models.Star{}, err
+		}
+		star.Dec = dec
+	}
+	return star, nil
+}
+
+// parseCoordinate принимает координату числом или строкой и проверяет диапазон
+func parseCoordinate(raw json.RawMessage, parse func(string) (float64, error)) (float64, error) {
+	var text string
+	if err := json.Unmarshal(raw, &text); err != nil {
+		var number float64
+		if err := json.Unmarshal(raw, &number); err != nil {
+			return 0, fmt.Errorf("координата должна быть числом или строкой: %s", raw)
+		}
+		text = strconv.FormatFloat(number, 'f', -1, 64)
+	}
+	return parse(text)
+}
+
+// validateCoordsFormat проверяет параметр ?coords= у запросов к звёздам
+func validateCoordsFormat(c *gin.Context) {
+	switch c.Query("coords") {
+	case "", coordsDecimal, coordsSexagesimal:
+		c.Next()
+	default:
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Параметр coords должен быть %s или %s", coordsDecimal, coordsSexagesimal),
+		})
+	}
+}
+
+// starJSON возвращает звезду в формате координат, запрошенном клиентом
+func starJSON(c *gin.Context, star models.Star) interface{} {
+	if c.Query("coords") != coordsSexagesimal {
+		return star
+	}
+	return sexagesimalStar{
+		Star: star,
+		RA:   astro.FormatRA(star.RA),
+		Dec:  astro.FormatDec(star.Dec),
+	}
+}
+
+func starsJSON(c *gin.Context, stars []models.Star) []interface{} {
+	list := make([]interface{}, 0, len(stars))
+	for _, star := range stars {
+		list = append(list, starJSON(c, star))
+	}
+	return list
+}
@@ -0,0 +1,259 @@
+package api
+
+import (
+	"Lab1/internal/app/astro"
+	"Lab1/internal/app/exposure"
+	"Lab1/internal/app/models"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exposureParams — параметры камеры и условий наблюдения для расчёта выдержки.
+// Незаданные параметры берутся по умолчанию; из snr и exposure_time задаётся ровно одно:
+// по snr рассчитывается выдержка, по exposure_time — отношение сигнал/шум.
+type exposureParams struct {
+	QuantumEfficiency *float64 `json:"quantum_efficiency"`
+	ReadNoise         *float64 `json:"read_noise"`
+	DarkCurrent       *float64 `json:"dark_current"`
+	PixelSize         *float64 `json:"pixel_size"`
+	Throughput        *float64 `json:"throughput"`
+	SkyBrightness     *float64 `json:"sky_brightness"`
+	Seeing            *float64 `json:"seeing"`
+	SNR               *float64 `json:"snr"`
+	ExposureTime      *float64 `json:"exposure_time"`
+}
+
+// Допустимые звёздная величина и воздушная масса для расчёта выдержки
+const (
+	minExposureMagnitude = -30.0
+	maxExposureMagnitude = 30.0
+	maxExposureAirmass   = 40.0
+)
+
+func valueOr(v *float64, def float64) float64 {
+	if v == nil {
+		return def
+	}
+	return *v
+}
+
+// cameraParams возвращает параметры камеры из справочника; без камеры — камеру по умолчанию
+func cameraParams(camera *models.Camera) exposure.Camera {
+	if camera == nil {
+		return exposure.DefaultCamera
+	}
+	return exposure.Camera{
+		QuantumEfficiency: camera.QuantumEfficiency,
+		ReadNoise:         camera.ReadNoise,
+		DarkCurrent:       camera.DarkCurrent,
+		PixelSize:         camera.PixelSize,
+	}
+}
+
+// setup собирает параметры расчёта для телескопа с апертурой и фокусным расстоянием в мм;
+// параметры камеры из запроса заменяют параметры camera
+func (p exposureParams) setup(aperture, focalLength, k float64, camera exposure.Camera) exposure.Setup {
+	return exposure.Setup{
+		Aperture:    aperture,
+		FocalLength: focalLength,
+		Camera: exposure.Camera{
+			QuantumEfficiency: valueOr(p.QuantumEfficiency, camera.QuantumEfficiency),
+			ReadNoise:         valueOr(p.ReadNoise, camera.ReadNoise),
+			DarkCurrent:       valueOr(p.DarkCurrent, camera.DarkCurrent),
+			PixelSize:         valueOr(p.PixelSize, camera.PixelSize),
+		},
+		Throughput:            valueOr(p.Throughput, exposure.DefaultThroughput),
+		SkyBrightness:         valueOr(p.SkyBrightness, exposure.DefaultSkyBrightness),
+		Seeing:                valueOr(p.Seeing, exposure.DefaultSeeing),
+		ExtinctionCoefficient: k,
+	}
+}
+
+// estimate рассчитывает выдержку по snr или отношение сигнал/шум по exposure_time
+func (p exposureParams) estimate(setup exposure.Setup, target exposure.Target) (exposure.Estimate, error) {
+	switch {
+	case p.SNR != nil && p.ExposureTime != nil:
+		return exposure.Estimate{}, errors.New("Нужно задать либо snr, либо exposure_time, но не оба")
+	case p.SNR != nil:
+		return setup.ExposureTime(target, *p.SNR)
+	case p.ExposureTime != nil:
+		return setup.SNR(target, *p.ExposureTime)
+	default:
+		return exposure.Estimate{}, errors.New("Нужно задать snr или exposure_time")
+	}
+}
+
+func roundTo(x float64, digits int) float64 {
+	p := math.Pow(10, float64(digits))
+	return math.Round(x*p) / p
+}
+
+func estimateJSON(e exposure.Estimate, target exposure.Target) gin.H {
+	return gin.H{
+		"exposure_time": roundTo(e.ExposureTime, 2),
+		"snr":           roundTo(e.SNR, 1),
+		"v_mag":         target.Magnitude,
+		"airmass":       roundTo(target.Airmass, 3),
+		"signal_rate":   roundTo(e.SignalRate, 2),
+		"sky_rate":      roundTo(e.SkyRate, 3),
+		"pixels":        roundTo(e.Pixels, 1),
+		"pixel_scale":   roundTo(e.PixelScale, 3),
+	}
+}
+
+// POST /api/exposure
+// Body JSON: { "v_mag":12.5, "airmass":1.2, "telescope_id":1, "snr":100,
+//
+//	"quantum_efficiency":0.6, "read_noise":10, "dark_current":0.1, "pixel_size":9,
+//	"sky_brightness":21, "seeing":2.5, "extinction":0.2 }
+//
+// Вместо v_mag можно передать star_id, вместо telescope_id — aperture и focal_length в мм,
+// camera_id берёт параметры камеры из справочника.
+// Вместо snr можно передать exposure_time (с), тогда рассчитывается отношение сигнал/шум.
+func calculateExposure(c *gin.Context) {
+	var req struct {
+		exposureParams
+		VMag        *float64 `json:"v_mag"`
+		StarID      *int     `json:"star_id"`
+		Airmass     *float64 `json:"airmass"`
+		TelescopeID *int     `json:"telescope_id"`
+		CameraID    *int     `json:"camera_id"`
+		Aperture    float64  `json:"aperture"`
+		FocalLength float64  `json:"focal_length"`
+		Extinction  *float64 `json:"extinction"`
+	}
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный JSON: " + err.Error()})
+		return
+	}
+
+	if req.StarID != nil {
+		star, err := repo.GetStarByID(*req.StarID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Звезда не найдена"})
+			return
+		}
+		req.VMag = star.VMag
+	}
+	if req.VMag == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Нужна звёздная величина v_mag или звезда с известной величиной"})
+		return
+	}
+
+	if *req.VMag < minExposureMagnitude || *req.VMag > maxExposureMagnitude || math.IsNaN(*req.VMag) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("v_mag должна быть в интервале [%g, %g]", minExposureMagnitude, maxExposureMagnitude)})
+		return
+	}
+	if req.Airmass != nil && (*req.Airmass < 1 || *req.Airmass > maxExposureAirmass) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("airmass должна быть в интервале [1, %g]", maxExposureAirmass)})
+		return
+	}
+
+	if req.TelescopeID != nil {
+		telescope, err := repo.GetTelescopeByID(*req.TelescopeID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Телескоп не найден"})
+			return
+		}
+		req.Aperture, req.FocalLength = telescope.Aperture, telescope.FocalLength
+	}
+
+	var camera *models.Camera
+	if req.CameraID != nil {
+		var err error
+		if camera, err = repo.GetCameraByID(*req.CameraID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Камера не найдена"})
+			return
+		}
+	}
+
+	k := valueOr(req.Extinction, astro.DefaultExtinctionCoefficient)
+	setup := req.setup(req.Aperture, req.FocalLength, k, cameraParams(camera))
+	target := exposure.Target{Magnitude: *req.VMag, Airmass: valueOr(req.Airmass, 1)}
+	estimate, err := req.estimate(setup, target)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, estimateJSON(estimate, target))
+}
+
+// PUT /api/orders/:id/stars/:star_id/exposure
+// Body JSON: { "snr":100 } или { "exposure_time":60 }, плюс необязательные параметры камеры и условий, как в /api/exposure.
+// Рассчитывает план выдержки звезды заявки для телескопа и камеры заявки при воздушной массе на момент наблюдения
+// и сохраняет выдержку и отношение сигнал/шум в звезде заявки.
+func planStarExposure(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный ID"})
+		return
+	}
+	starID, err := strconv.Atoi(c.Param("star_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный ID звезды"})
+		return
+	}
+
+	var req exposureParams
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный JSON: " + err.Error()})
+		return
+	}
+
+	order, err := repo.GetOrder(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Заявка не найдена"})
+		return
+	}
+	if order.Status != "черновик" && order.Status != "сформирован" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "План выдержки можно составить только для черновика или сформированной заявки"})
+		return
+	}
+	if order.Telescope == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "В заявке не выбран телескоп"})
+		return
+	}
+
+	target := findOrderStar(order, starID)
+	if target == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Звезды нет в заявке"})
+		return
+	}
+	if target.Star.VMag == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "У звезды не указана звёздная величина V"})
+		return
+	}
+
+	airmass, ok := order.AirmassAt(target.Star)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Звезда под горизонтом в момент наблюдения"})
+		return
+	}
+
+	setup := req.setup(order.Telescope.Aperture, order.Telescope.FocalLength, order.Observer().ExtinctionCoefficient(), cameraParams(order.Camera))
+	star := exposure.Target{Magnitude: *target.Star.VMag, Airmass: airmass}
+	estimate, err := req.estimate(setup, star)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result := estimateJSON(estimate, star)
+	err = repo.UpdateObservationStar(id, starID, map[string]interface{}{
+		"exposure_time": result["exposure_time"],
+		"snr":           result["snr"],
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка сохранения плана выдержки: " + err.Error()})
+		return
+	}
+
+	result["star_id"] = starID
+	result["telescope"] = order.Telescope.Name
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"Lab1/internal/app/models"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// starFilter — фильтры списка звёзд по галактическим и эклиптическим координатам и фотометрии
+//
+//	gal_abs_b_min / gal_abs_b_max     — ограничения на |b|, градусы
+//	gal_l_min / gal_l_max             — интервал галактической долготы (может проходить через 0°)
+//	ecl_abs_lat_min / ecl_abs_lat_max — ограничения на |β|, градусы
+//	vmag_min / vmag_max               — ограничения на звёздную величину V
+//	bv_min / bv_max                   — ограничения на показатель цвета B−V
+//	sp                                — спектральные классы через запятую, например "G,K0"
+//	sort                              — v_mag, b_v, spectral_type или star_name; "-" в начале — по убыванию
+//
+// Звёзды без нужной величины не проходят фильтры по ней и при сортировке оказываются в конце.
+type starFilter struct {
+	GalAbsBMin, GalAbsBMax     *float64
+	GalLMin, GalLMax           *float64
+	EclAbsLatMin, EclAbsLatMax *float64
+	VMagMin, VMagMax           *float64
+	BVMin, BVMax               *float64
+	SpectralClasses            []string
+	Sort                       string
+	Descending                 bool
+}
+
+func parseStarFilter(c *gin.Context) (f starFilter, err error) {
+	params := []struct {
+		name string
+		dst  **float64
+	}{
+		{"gal_abs_b_min", &f.GalAbsBMin},
+		{"gal_abs_b_max", &f.GalAbsBMax},
+		{"gal_l_min", &f.GalLMin},
+		{"gal_l_max", &f.GalLMax},
+		{"ecl_abs_lat_min", &f.EclAbsLatMin},
+		{"ecl_abs_lat_max", &f.EclAbsLatMax},
+		{"vmag_min", &f.VMagMin},
+		{"vmag_max", &f.VMagMax},
+		{"bv_min", &f.BVMin},
+		{"bv_max", &f.BVMax},
+	}
+
+	for _, p := range params {
+		raw := c.Query(p.name)
+		if raw == "" {
+			continue
+		}
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil || math.IsNaN(v) {
+			return f, fmt.Errorf("Параметр %s должен быть числом", p.name)
+		}
+		*p.dst = &v
+	}
+
+	for _, class := range strings.Split(c.Query("sp"), ",") {
+		if class = strings.TrimSpace(class); class != "" {
+			f.SpectralClasses = append(f.SpectralClasses, class)
+		}
+	}
+
+	f.Sort, f.Descending, err = models.ParseStarSort(c.Query("sort"))
+	return f, err
+}
+
+// outside проверяет, что величина не задана или лежит вне интервала [lo, hi]
+func outside(v, lo, hi *float64) bool {
+	if lo == nil && hi == nil {
+		return false
+	}
+	if v == nil {
+		return true
+	}
+	return (lo != nil && *v < *lo) || (hi != nil && *v > *hi)
+}
+
+// match проверяет звезду; галактические и эклиптические координаты должны быть рассчитаны
+func (f starFilter) match(s models.Star) bool {
+	absB := math.Abs(s.Galactic.B)
+	absBeta := math.Abs(s.Ecliptic.Lat)
+
+	if f.GalAbsBMin != nil && absB < *f.GalAbsBMin {
+		return false
+	}
+	if f.GalAbsBMax != nil && absB > *f.GalAbsBMax {
+		return false
+	}
+	if f.EclAbsLatMin != nil && absBeta < *f.EclAbsLatMin {
+		return false
+	}
+	if f.EclAbsLatMax != nil && absBeta > *f.EclAbsLatMax {
+		return false
+	}
+	if outside(s.VMag, f.VMagMin, f.VMagMax) || outside(s.BV, f.BVMin, f.BVMax) {
+		return false
+	}
+	if len(f.SpectralClasses) > 0 {
+		found := false
+		for _, class := range f.SpectralClasses {
+			if s.HasSpectralClass(class) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.GalLMin != nil || f.GalLMax != nil {
+		lo, hi := 0.0, 360.0
+		if f.GalLMin != nil {
+			lo = *f.GalLMin
+		}
+		if f.GalLMax != nil {
+			hi = *f.GalLMax
+		}
+		l := s.Galactic.L
+		// Интервал вида 350..10 проходит через нулевую долготу
+		if lo <= hi && (l < lo || l > hi) {
+			return false
+		}
+		if lo > hi && l < lo && l > hi {
+			return false
+		}
+	}
+	return true
+}
+
+// apply рассчитывает координаты в других системах и оставляет подходящие звёзды
+func (f starFilter) apply(stars []models.Star) []models.Star {
+	result := make([]models.Star, 0, len(stars))
+	for _, s := range stars {
+		s.FillFrames()
+		if f.match(s) {
+			result = append(result, s)
+		}
+	}
+	models.SortStars(result, f.Sort, f.Descending)
+	return result
+}
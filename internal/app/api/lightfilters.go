@@ -0,0 +1,165 @@
+package api
+
+import (
+	"Lab1/internal/app/models"
+	"Lab1/internal/app/repository"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func InitLightFilterAPI(database *gorm.DB, r *gin.RouterGroup) {
+	db = database
+	repo = repository.NewRepositoryFromDB(db)
+	registerLightFilterRoutes(r)
+}
+
+// Светофильтры обсерватории
+func registerLightFilterRoutes(r *gin.RouterGroup) {
+	lightFilters := r.Group("/filters")
+	{
+		lightFilters.GET("", getLightFilters)
+		lightFilters.GET("/:id", getLightFilterByID)
+		lightFilters.POST("", createLightFilter)
+		lightFilters.PUT("/:id", updateLightFilter)
+		lightFilters.DELETE("/:id", deleteLightFilter)
+	}
+}
+
+// validateLightFilter проверяет параметры светофильтра
+func validateLightFilter(f *models.Filter) error {
+	f.Name = strings.TrimSpace(f.Name)
+	f.System = strings.TrimSpace(f.System)
+	if f.Name == "" {
+		return errors.New("Обозначение фильтра обязательно")
+	}
+	if f.Wavelength < 0 || f.Bandwidth < 0 {
+		return errors.New("Длина волны и ширина полосы не могут быть отрицательными")
+	}
+	return nil
+}
+
+func getLightFilters(c *gin.Context) {
+	filters, err := repo.GetFilters()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка получения фильтров: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, filters)
+}
+
+func getLightFilterByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный ID"})
+		return
+	}
+
+	filter, err := repo.GetFilterByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Фильтр не найден"})
+		return
+	}
+	c.JSON(http.StatusOK, filter)
+}
+
+func createLightFilter(c *gin.Context) {
+	var input models.Filter
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный JSON: " + err.Error()})
+		return
+	}
+	input.FilterID = 0
+
+	if err := validateLightFilter(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := repo.CreateFilter(&input); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка сохранения в БД: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Фильтр добавлен",
+		"filter":  input,
+	})
+}
+
+func updateLightFilter(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный ID"})
+		return
+	}
+
+	if _, err := repo.GetFilterByID(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Фильтр не найден"})
+		return
+	}
+
+	var input models.Filter
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный JSON: " + err.Error()})
+		return
+	}
+
+	if err := validateLightFilter(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = repo.UpdateFilterFields(id, map[string]interface{}{
+		"name":       input.Name,
+		"system":     input.System,
+		"wavelength": input.Wavelength,
+		"bandwidth":  input.Bandwidth,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при обновлении: " + err.Error()})
+		return
+	}
+
+	filter, _ := repo.GetFilterByID(id)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Фильтр обновлён",
+		"filter":  filter,
+	})
+}
+
+func deleteLightFilter(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный ID"})
+		return
+	}
+
+	if _, err := repo.GetFilterByID(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Фильтр не найден"})
+		return
+	}
+
+	// Шаги последовательностей съёмки ссылаются на фильтр
+	count, err := repo.CountFilterExposures(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка проверки заявок: " + err.Error()})
+		return
+	}
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Фильтр используется в последовательностях съёмки: %d", count)})
+		return
+	}
+
+	if err := repo.DeleteFilter(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при удалении фильтра"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Фильтр удалён"})
+}
@@ -2,14 +2,23 @@ package api
 
 import (
 	"Lab1/internal/app/auth"
+	"Lab1/internal/app/calculator"
+	"Lab1/internal/app/config"
+	"Lab1/internal/app/export"
 	"Lab1/internal/app/models"
+	"Lab1/internal/app/planner"
 	"Lab1/internal/app/repository"
+	"Lab1/internal/app/skychart"
+	"fmt"
+	"io"
 	"math"
 	"net/http"
+	"sort"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
@@ -25,9 +34,16 @@ func registerOrderRoutes(r *gin.RouterGroup) {
 		orders.GET("/cart", getCartInfo)
 		orders.GET("", getAllOrders)
 		orders.GET("/:id", getOrderByID)
+		orders.GET("/:id/export", exportOrder)
+		orders.GET("/:id/skychart.svg", getOrderSkyChart)
+		orders.GET("/:id/stellarium", exportOrderStellarium)
+		orders.GET("/:id/kstars", exportOrderKStars)
 		orders.PUT("/:id", updateOrderFields)
 		orders.PUT("/:id/submit", submitOrder) // ✅ сформировать
 		orders.PUT("/:id/complete", completeOrder)
+		orders.POST("/:id/schedule", scheduleOrder)
+		orders.PUT("/:id/stars/:star_id/exposure", planStarExposure)
+		orders.PUT("/:id/stars/:star_id/sequence", putStarSequence)
 		orders.DELETE("/:id", deleteOrder)
 
 		orders.DELETE("/telescope-observation-stars", deleteObservationStar)
@@ -101,17 +117,132 @@ func getOrderByID(c *gin.Context) {
 
 	var order models.TelescopeObservation
 	if err := db.
+		Preload("TelescopeObservationStars", repository.ByOrderNumber).
 		Preload("TelescopeObservationStars.Star").
+		Preload("TelescopeObservationStars.Exposures", repository.ByPosition).
+		Preload("TelescopeObservationStars.Exposures.Filter").
 		Preload("Creator").
 		Preload("Moderator").
+		Preload("Site").
+		Preload("Telescope").
+		Preload("Camera").
 		First(&order, "telescope_observation_id = ? AND status <> ?", id, "удалён").Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Заявка не найдена"})
 		return
 	}
 
+	order.FillObservationTime()
+	order.FillVisibility()
+	order.FillSky()
+	duration := planner.EstimateDuration(&order)
+	order.EstimatedDuration = &duration
+
 	c.JSON(http.StatusOK, order)
 }
 
+// GET /api/orders/:id/export?format=votable|csv
+// Выгрузка звёзд заявки вместе с результатами расчёта
+func exportOrder(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный ID"})
+		return
+	}
+
+	format := c.DefaultQuery("format", export.FormatVOTable)
+	w, err := export.NewWriter(format, c.Writer)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	order, err := repo.GetOrder(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Заявка не найдена"})
+		return
+	}
+
+	// Единицы и UCD результата берутся у калькулятора, которым завершена заявка
+	var calc calculator.Calculator
+	if order.Calculator != "" {
+		calc, _ = calculator.Get(order.Calculator)
+	}
+
+	stars := order.TelescopeObservationStars
+	sort.SliceStable(stars, func(i, j int) bool { return stars[i].OrderNumber < stars[j].OrderNumber })
+
+	c.Header("Content-Type", export.ContentType(format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="observation_%d.%s"`, id, export.Extension(format)))
+	c.Status(http.StatusOK)
+
+	err = w.WriteHeader(export.OrderTable(order, calc))
+	for i := 0; err == nil && i < len(stars); i++ {
+		err = w.WriteRow(export.OrderRow(stars[i]))
+	}
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		logrus.Error("Ошибка выгрузки заявки: ", err)
+	}
+}
+
+// GET /api/orders/:id/skychart.svg
+// Полярная карта неба со звёздами заявки для её места и даты наблюдения
+func getOrderSkyChart(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный ID"})
+		return
+	}
+
+	order, err := repo.GetOrder(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Заявка не найдена"})
+		return
+	}
+
+	c.Header("Content-Type", "image/svg+xml")
+	c.Status(http.StatusOK)
+	if err := skychart.Render(c.Writer, order); err != nil {
+		logrus.Error("Ошибка построения карты неба: ", err)
+	}
+}
+
+// GET /api/orders/:id/stellarium
+// Список наблюдений для плагина Observing list в Stellarium
+func exportOrderStellarium(c *gin.Context) {
+	writeOrderPlan(c, "application/json", "stellarium.json", export.WriteStellarium)
+}
+
+// GET /api/orders/:id/kstars
+// Zip-архив с планом для планировщика KStars/Ekos (.esl) и последовательностями съёмки (.esq)
+func exportOrderKStars(c *gin.Context) {
+	writeOrderPlan(c, "application/zip", "kstars.zip", export.WriteKStars)
+}
+
+// writeOrderPlan отдаёт план наблюдения заявки файлом, записанным функцией write
+func writeOrderPlan(c *gin.Context, contentType, suffix string, write func(io.Writer, *models.TelescopeObservation) error) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный ID"})
+		return
+	}
+
+	order, err := repo.GetOrder(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Заявка не найдена"})
+		return
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="observation_%d_%s"`, id, suffix))
+	c.Status(http.StatusOK)
+	if err := write(c.Writer, order); err != nil {
+		logrus.Error("Ошибка выгрузки плана наблюдения: ", err)
+	}
+}
+
 func updateOrderFields(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
@@ -133,6 +264,93 @@ func updateOrderFields(c *gin.Context) {
 	delete(payload, "created_at")
 	delete(payload, "formation_date")
 	delete(payload, "completion_date")
+	delete(payload, "calculator_version")
+
+	order, err := repo.GetOrder(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Заявка не найдена"})
+		return
+	}
+	loc := order.Location()
+
+	// Координаты, указанные вручную, отвязывают заявку от места из справочника
+	_, hasLat := payload["observer_latitude"]
+	_, hasLon := payload["observer_longitude"]
+	if hasLat || hasLon {
+		if rawSiteID, ok := payload["site_id"]; ok && rawSiteID != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Нельзя одновременно задать site_id и координаты наблюдателя"})
+			return
+		}
+		payload["site_id"] = nil
+	}
+
+	// Место наблюдения из справочника задаёт координаты заявки
+	if rawSiteID, ok := payload["site_id"]; ok {
+		if rawSiteID == nil {
+			payload["site_id"] = nil
+			loc = time.UTC
+		} else {
+			siteID, ok := rawSiteID.(float64)
+			if !ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "site_id должен быть числом"})
+				return
+			}
+			site, err := repo.GetSiteByID(int(siteID))
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Место наблюдения не найдено"})
+				return
+			}
+			payload["site_id"] = site.SiteID
+			payload["observer_latitude"] = site.Latitude
+			payload["observer_longitude"] = site.Longitude
+			loc = site.Location()
+		}
+	}
+
+	if rawTelescopeID, ok := payload["telescope_id"]; ok && rawTelescopeID != nil {
+		telescopeID, ok := rawTelescopeID.(float64)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "telescope_id должен быть числом"})
+			return
+		}
+		if _, err := repo.GetTelescopeByID(int(telescopeID)); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Телескоп не найден"})
+			return
+		}
+		payload["telescope_id"] = int(telescopeID)
+	}
+
+	if rawCameraID, ok := payload["camera_id"]; ok && rawCameraID != nil {
+		cameraID, ok := rawCameraID.(float64)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "camera_id должен быть числом"})
+			return
+		}
+		if _, err := repo.GetCameraByID(int(cameraID)); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Камера не найдена"})
+			return
+		}
+		payload["camera_id"] = int(cameraID)
+	}
+
+	// Дата без смещения считается местным временем места наблюдения и переводится в UTC
+	if rawDate, ok := payload["observation_date"]; ok && rawDate != nil {
+		text, _ := rawDate.(string)
+		observedAt, err := parseObservationDate(text, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		payload["observation_date"] = observedAt
+	}
+
+	if name, ok := payload["calculator"]; ok {
+		nameStr, _ := name.(string)
+		if _, err := calculator.Get(nameStr); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
 
 	if err := db.Model(&models.TelescopeObservation{}).
 		Where("telescope_observation_id = ?", id).
@@ -174,6 +392,17 @@ func submitOrder(c *gin.Context) {
 		return
 	}
 
+	// Звёзды слабее предела телескопа при текущей воздушной массе не наблюдаемы:
+	// без force=true заявка не формируется, с ним — формируется с предупреждением
+	tooFaint := faintStars(order)
+	if len(tooFaint) > 0 && c.Query("force") != "true" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":     "Звёзды слабее предельной величины телескопа; уберите их или повторите запрос с force=true",
+			"too_faint": tooFaint,
+		})
+		return
+	}
+
 	now := time.Now()
 	order.Status = "сформирован"
 	order.FormationDate = &now
@@ -184,11 +413,145 @@ func submitOrder(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Заявка успешно сформирована",
-		"id":      order.TelescopeObservationID,
+		"message":   "Заявка успешно сформирована",
+		"id":        order.TelescopeObservationID,
+		"warnings":  moonWarnings(order),
+		"too_faint": tooFaint,
+	})
+}
+
+// POST /api/orders/:id/schedule
+// Body JSON (необязательно): { "from":"2025-01-15T20:00", "to":"2025-01-16T05:00" }
+// Составляет расписание наблюдения звёзд заявки: порядок и плановое время начала.
+// Без from/to берётся тёмное время ночи даты наблюдения.
+func scheduleOrder(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный ID"})
+		return
+	}
+
+	var req struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный JSON"})
+			return
+		}
+	}
+
+	order, err := repo.GetOrder(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Заявка не найдена"})
+		return
+	}
+	if order.Status != "черновик" && order.Status != "сформирован" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Расписание можно составить только для черновика или сформированной заявки"})
+		return
+	}
+	if len(order.TelescopeObservationStars) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "В заявке нет звёзд"})
+		return
+	}
+
+	observer := order.Observer()
+	loc := order.Location()
+	window, err := planner.ParseWindow(observer, loc, req.From, req.To, order.ObservedAt())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	schedule := planner.ForOrder(order, window).Plan(order.TelescopeObservationStars)
+	if err := repo.SaveSchedule(id, schedule); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка сохранения расписания: " + err.Error()})
+		return
+	}
+
+	names := map[int]string{}
+	for _, s := range order.TelescopeObservationStars {
+		names[s.StarID] = s.Star.StarName
+	}
+	targets := []gin.H{}
+	for _, t := range schedule.Targets {
+		targets = append(targets, gin.H{
+			"star_id":       t.StarID,
+			"star_name":     names[t.StarID],
+			"order_number":  t.OrderNumber,
+			"planned_start": t.Start.In(loc),
+			"planned_end":   t.End.In(loc),
+			"altitude":      t.Altitude,
+			"slew":          t.Slew,
+		})
+	}
+	unscheduled := []gin.H{}
+	for _, starID := range schedule.Unscheduled {
+		unscheduled = append(unscheduled, gin.H{"star_id": starID, "star_name": names[starID]})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"from":        window.Start.In(loc),
+		"to":          window.End.In(loc),
+		"targets":     targets,
+		"unscheduled": unscheduled,
 	})
 }
 
+// moonWarnings возвращает предупреждения о звёздах заявки, слишком близких к яркой Луне
+// над горизонтом. Пороги берутся из конфига.
+func moonWarnings(order *models.TelescopeObservation) []gin.H {
+	warnings := []gin.H{}
+	maxSeparation, minIllumination := config.MoonWarningLimits()
+
+	order.FillSky()
+	sky := order.Sky
+	if sky.MoonAltitude <= 0 || sky.MoonPhase.Illumination < minIllumination {
+		return warnings
+	}
+
+	for _, s := range order.TelescopeObservationStars {
+		if s.MoonSeparation == nil || *s.MoonSeparation >= maxSeparation {
+			continue
+		}
+		warnings = append(warnings, gin.H{
+			"star_id":           s.StarID,
+			"star_name":         s.Star.StarName,
+			"moon_separation":   *s.MoonSeparation,
+			"moon_illumination": math.Round(sky.MoonPhase.Illumination*100) / 100,
+			"message": fmt.Sprintf("%s в %.1f° от Луны (освещённость %.0f%%)",
+				s.Star.StarName, *s.MoonSeparation, sky.MoonPhase.Illumination*100),
+		})
+	}
+	return warnings
+}
+
+// faintStars возвращает звёзды заявки, которые слабее предельной величины выбранного телескопа
+// с учётом экстинкции. Звёзды без величины V и под горизонтом не проверяются.
+func faintStars(order *models.TelescopeObservation) []gin.H {
+	faint := []gin.H{}
+	for _, s := range order.TelescopeObservationStars {
+		if s.Star.VMag == nil {
+			continue
+		}
+		limit, airmass, ok := order.LimitingMagnitudeAt(s.Star)
+		if !ok || *s.Star.VMag <= limit {
+			continue
+		}
+		faint = append(faint, gin.H{
+			"star_id":            s.StarID,
+			"star_name":          s.Star.StarName,
+			"v_mag":              *s.Star.VMag,
+			"airmass":            math.Round(airmass*1000) / 1000,
+			"limiting_magnitude": math.Round(limit*100) / 100,
+			"message": fmt.Sprintf("%s (V = %.2f) слабее предела телескопа %s: %.2f при воздушной массе %.2f",
+				s.Star.StarName, *s.Star.VMag, order.Telescope.Name, limit, airmass),
+		})
+	}
+	return faint
+}
+
 func completeOrder(c *gin.Context) {
 	userID := auth.CurrentUserID()
 
@@ -246,11 +609,15 @@ func completeOrder(c *gin.Context) {
 		return
 	}
 
-	for _, s := range stars {
-		value := math.Sqrt(math.Pow(s.Star.RA, 2) + math.Pow(s.Star.Dec, 2))
-		result := math.Round(value*100) / 100
+	// Результаты считает калькулятор, выбранный в заявке
+	results, err := calculator.Complete(order, stars)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Ошибка выбора калькулятора: " + err.Error()})
+		return
+	}
 
-		if err := repo.UpdateObservationStarResult(id, s.StarID, result); err != nil {
+	for _, res := range results {
+		if err := repo.UpdateObservationStarResult(id, res.StarID, res.Value, res.Airmass, res.Extinction); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка сохранения результата: " + err.Error()})
 			return
 		}
@@ -346,3 +713,12 @@ func putObservationStar(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "М-М запись обновлена"})
 }
+
+// parseObservationDate разбирает дату наблюдения; дата без смещения считается местным временем loc
+func parseObservationDate(text string, loc *time.Location) (time.Time, error) {
+	t, err := planner.ParseTime(text, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Некорректная дата наблюдения: %w", err)
+	}
+	return t, nil
+}
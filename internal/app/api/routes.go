@@ -20,4 +20,10 @@ func RegisterRoutes(r *gin.Engine, db *gorm.DB) {
 	InitStarAPI(db, api)
 	InitOrderAPI(db, api)
 	InitUserAPI(db, api)
+	InitCalculatorAPI(db, api)
+	InitSiteAPI(db, api)
+	InitTelescopeAPI(db, api)
+	InitCameraAPI(db, api)
+	InitLightFilterAPI(db, api)
+	InitCalendarAPI(db, api)
 }
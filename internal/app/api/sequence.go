@@ -0,0 +1,111 @@
+package api
+
+import (
+	"Lab1/internal/app/models"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// findOrderStar возвращает звезду заявки по идентификатору звезды
+func findOrderStar(order *models.TelescopeObservation, starID int) *models.TelescopeObservationStar {
+	for i := range order.TelescopeObservationStars {
+		if order.TelescopeObservationStars[i].StarID == starID {
+			return &order.TelescopeObservationStars[i]
+		}
+	}
+	return nil
+}
+
+// PUT /api/orders/:id/stars/:star_id/sequence
+// Body JSON: { "steps":[{"filter_id":1,"count":3,"exposure_time":60}, {"filter_id":2,"count":3}] }
+// Заменяет последовательность съёмки звезды заявки: шаги выполняются по порядку,
+// каждый — count кадров с фильтром. Без exposure_time берётся плановая выдержка звезды
+// (PUT /api/orders/:id/stars/:star_id/exposure). Пустой steps удаляет последовательность,
+// тогда звезда снова наблюдается по quantity.
+func putStarSequence(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный ID"})
+		return
+	}
+	starID, err := strconv.Atoi(c.Param("star_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный ID звезды"})
+		return
+	}
+
+	var req struct {
+		Steps []struct {
+			FilterID     int      `json:"filter_id"`
+			Count        int      `json:"count"`
+			ExposureTime *float64 `json:"exposure_time"`
+		} `json:"steps"`
+	}
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный JSON: " + err.Error()})
+		return
+	}
+
+	order, err := repo.GetOrder(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Заявка не найдена"})
+		return
+	}
+	if order.Status != "черновик" && order.Status != "сформирован" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Последовательность съёмки можно изменить только у черновика или сформированной заявки"})
+		return
+	}
+
+	target := findOrderStar(order, starID)
+	if target == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Звезды нет в заявке"})
+		return
+	}
+
+	steps := make([]models.ObservationExposure, 0, len(req.Steps))
+	for i, step := range req.Steps {
+		filter, err := repo.GetFilterByID(step.FilterID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Шаг %d: фильтр не найден", i+1)})
+			return
+		}
+		if step.Count < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Шаг %d: число кадров должно быть положительным", i+1)})
+			return
+		}
+
+		exposureTime := target.ExposureTime
+		if step.ExposureTime != nil {
+			exposureTime = step.ExposureTime
+		}
+		if exposureTime == nil || *exposureTime <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Шаг %d: нужна положительная выдержка exposure_time", i+1)})
+			return
+		}
+
+		steps = append(steps, models.ObservationExposure{
+			FilterID:     filter.FilterID,
+			Count:        step.Count,
+			ExposureTime: *exposureTime,
+			Filter:       filter,
+		})
+	}
+
+	if err := repo.SaveSequence(id, starID, steps); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка сохранения последовательности: " + err.Error()})
+		return
+	}
+
+	target.Exposures = steps
+	exposure, readout := target.SequenceDuration(order.ReadoutTime())
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Последовательность съёмки сохранена",
+		"star_id":       starID,
+		"steps":         steps,
+		"exposure_time": exposure,
+		"readout_time":  readout,
+	})
+}
@@ -0,0 +1,182 @@
+package api
+
+import (
+	"Lab1/internal/app/models"
+	"Lab1/internal/app/repository"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func InitSiteAPI(database *gorm.DB, r *gin.RouterGroup) {
+	db = database
+	repo = repository.NewRepositoryFromDB(db)
+	registerSiteRoutes(r)
+}
+
+func registerSiteRoutes(r *gin.RouterGroup) {
+	sites := r.Group("/sites")
+	{
+		sites.GET("", getSites)
+		sites.GET("/:id", getSiteByID)
+		sites.POST("", createSite)
+		sites.PUT("/:id", updateSite)
+		sites.DELETE("/:id", deleteSite)
+	}
+}
+
+// validateSite проверяет координаты, часовой пояс и ограничение по горизонту места наблюдения
+func validateSite(site *models.ObservingSite) error {
+	site.Name = strings.TrimSpace(site.Name)
+	if site.Name == "" {
+		return errors.New("Название места обязательно")
+	}
+	if site.Latitude < -90 || site.Latitude > 90 {
+		return errors.New("Широта должна быть в диапазоне [-90, 90]")
+	}
+	if site.Longitude < -180 || site.Longitude > 180 {
+		return errors.New("Долгота должна быть в диапазоне [-180, 180]")
+	}
+	if site.Elevation < -500 || site.Elevation > 9000 {
+		return errors.New("Высота над уровнем моря должна быть в диапазоне [-500, 9000] м")
+	}
+	if site.HorizonLimit < 0 || site.HorizonLimit >= 90 {
+		return errors.New("Ограничение по горизонту должно быть в диапазоне [0, 90) градусов")
+	}
+	if site.Timezone == "" {
+		site.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(site.Timezone); err != nil {
+		return fmt.Errorf("Неизвестный часовой пояс: %s", site.Timezone)
+	}
+	return nil
+}
+
+func getSites(c *gin.Context) {
+	sites, err := repo.GetSites()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка получения мест наблюдения: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, sites)
+}
+
+func getSiteByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный ID"})
+		return
+	}
+
+	site, err := repo.GetSiteByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Место наблюдения не найдено"})
+		return
+	}
+	c.JSON(http.StatusOK, site)
+}
+
+func createSite(c *gin.Context) {
+	var input models.ObservingSite
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный JSON: " + err.Error()})
+		return
+	}
+	input.SiteID = 0
+
+	if err := validateSite(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := repo.CreateSite(&input); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка сохранения в БД: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Место наблюдения добавлено",
+		"site":    input,
+	})
+}
+
+func updateSite(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный ID"})
+		return
+	}
+
+	if _, err := repo.GetSiteByID(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Место наблюдения не найдено"})
+		return
+	}
+
+	var input models.ObservingSite
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный JSON: " + err.Error()})
+		return
+	}
+
+	if err := validateSite(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = repo.UpdateSiteFields(id, map[string]interface{}{
+		"name":          input.Name,
+		"latitude":      input.Latitude,
+		"longitude":     input.Longitude,
+		"elevation":     input.Elevation,
+		"timezone":      input.Timezone,
+		"horizon_limit": input.HorizonLimit,
+		"is_default":    input.IsDefault,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при обновлении: " + err.Error()})
+		return
+	}
+
+	site, _ := repo.GetSiteByID(id)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Место наблюдения обновлено",
+		"site":    site,
+	})
+}
+
+func deleteSite(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный ID"})
+		return
+	}
+
+	if _, err := repo.GetSiteByID(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Место наблюдения не найдено"})
+		return
+	}
+
+	// Заявки хранят ссылку на место, поэтому используемое место удалить нельзя
+	count, err := repo.CountSiteOrders(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка проверки заявок: " + err.Error()})
+		return
+	}
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Место используется в заявках: %d", count)})
+		return
+	}
+
+	if err := repo.DeleteSite(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при удалении места наблюдения"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Место наблюдения удалено"})
+}
@@ -1,17 +1,25 @@
 package api
 
 import (
+	"Lab1/internal/app/astro"
 	"Lab1/internal/app/auth"
+	"Lab1/internal/app/catalog"
 	"Lab1/internal/app/config"
+	"Lab1/internal/app/export"
 	"Lab1/internal/app/models"
+	"Lab1/internal/app/planner"
 	"Lab1/internal/app/repository"
 	"context"
 	"fmt"
+	"math"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/minio/minio-go/v7"
+	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
@@ -26,10 +34,15 @@ func InitStarAPI(database *gorm.DB, r *gin.RouterGroup) {
 
 func registerStarRoutes(r *gin.RouterGroup) {
 	stars := r.Group("/stars")
+	stars.Use(validateCoordsFormat)
 	{
 		stars.GET("", getStars)
+		stars.GET("/near", getStarsNear)
+		stars.GET("/visible", getVisibleStars)
+		stars.GET("/export", exportStars)
 		stars.GET("/:id", getStarByID)
 		stars.POST("", createStar)
+		stars.POST("/import", importStars)
 
 		stars.PUT("/:id", updateStar)
 		stars.DELETE("/:id", deleteStar)
@@ -39,12 +52,133 @@ func registerStarRoutes(r *gin.RouterGroup) {
 }
 
 func getStars(c *gin.Context) {
+	filter, err := parseStarFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	var stars []models.Star
 	if err := db.Find(&stars).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка получения звёзд: " + err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, stars)
+	c.JSON(http.StatusOK, starsJSON(c, filter.apply(stars)))
+}
+
+// GET /api/stars/near?ra=83.63&dec=22.01&radius=2
+// Поиск звёзд в конусе радиуса radius (градусы) вокруг точки ICRS J2000, по возрастанию расстояния
+func getStarsNear(c *gin.Context) {
+	ra, errRA := strconv.ParseFloat(c.Query("ra"), 64)
+	dec, errDec := strconv.ParseFloat(c.Query("dec"), 64)
+	radius, errRadius := strconv.ParseFloat(c.Query("radius"), 64)
+	if errRA != nil || errDec != nil || errRadius != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Нужны числовые параметры ra, dec и radius"})
+		return
+	}
+	if ra < 0 || ra >= 360 || dec < -90 || dec > 90 || radius <= 0 || radius > 180 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Параметры вне допустимого диапазона"})
+		return
+	}
+
+	stars, err := repo.GetStarsNear(ra, dec, radius)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка поиска звёзд: " + err.Error()})
+		return
+	}
+
+	result := make([]gin.H, 0, len(stars))
+	for _, star := range stars {
+		sra, sdec := star.J2000Position()
+		result = append(result, gin.H{
+			"star":       starJSON(c, star),
+			"separation": astro.AngularSeparation(ra, dec, sra, sdec),
+		})
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// GET /api/stars/visible?site=1&from=2025-01-15T20:00&to=2025-01-16T05:00&min_alt=30
+// Звёзды, поднимающиеся выше min_alt градусов за промежуток, по убыванию наибольшей высоты.
+// Время без смещения считается местным временем места; без from/to берётся ближайшая ночь.
+func getVisibleStars(c *gin.Context) {
+	site, ok := siteFromQuery(c)
+	if !ok {
+		return
+	}
+	loc := site.Location()
+	observer := site.Observer()
+
+	window, err := planner.ParseWindow(observer, loc, c.Query("from"), c.Query("to"), time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	minAltitude := 0.0
+	if text := c.Query("min_alt"); text != "" {
+		minAltitude, err = strconv.ParseFloat(text, 64)
+		if err != nil || minAltitude < -90 || minAltitude > 90 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Параметр min_alt должен быть числом от -90 до 90"})
+			return
+		}
+	}
+
+	visibility := planner.NewVisibility(observer, window, minAltitude)
+	err = repo.EachStar(func(star models.Star) error {
+		visibility.Add(star)
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка получения звёзд: " + err.Error()})
+		return
+	}
+
+	result := []gin.H{}
+	for _, v := range visibility.Stars() {
+		result = append(result, gin.H{
+			"star":          starJSON(c, v.Star),
+			"peak_altitude": math.Round(v.PeakAltitude*100) / 100,
+			"peak_time":     v.PeakTime.In(loc),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"site":         site,
+		"from":         window.Start.In(loc),
+		"to":           window.End.In(loc),
+		"min_altitude": visibility.MinAltitude,
+		"stars":        result,
+	})
+}
+
+// GET /api/stars/export?format=votable|csv
+// Потоковая выгрузка каталога звёзд
+func exportStars(c *gin.Context) {
+	format := c.DefaultQuery("format", export.FormatVOTable)
+	w, err := export.NewWriter(format, c.Writer)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", export.ContentType(format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="stars.%s"`, export.Extension(format)))
+	c.Status(http.StatusOK)
+
+	err = w.WriteHeader(export.StarTable())
+	if err == nil {
+		err = repo.EachStar(func(star models.Star) error {
+			return w.WriteRow(export.StarRow(star))
+		})
+	}
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		// Заголовки уже отправлены, остаётся только оборвать выгрузку
+		logrus.Error("Ошибка выгрузки каталога звёзд: ", err)
+	}
 }
 
 func getStarByID(c *gin.Context) {
@@ -54,14 +188,14 @@ func getStarByID(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Звезда не найдена"})
 		return
 	}
-	c.JSON(http.StatusOK, star)
+	star.FillFrames()
+	c.JSON(http.StatusOK, starJSON(c, star))
 }
 
 func createStar(c *gin.Context) {
-	var input models.Star
-
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный JSON: " + err.Error()})
+	input, err := bindStar(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -70,6 +204,11 @@ func createStar(c *gin.Context) {
 		return
 	}
 
+	if err := normalizeStarFrame(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	if err := db.Create(&input).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка сохранения в БД: " + err.Error()})
 		return
@@ -77,7 +216,7 @@ func createStar(c *gin.Context) {
 
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Звезда успешно добавлена",
-		"star":    input,
+		"star":    starJSON(c, input),
 	})
 }
 
@@ -97,20 +236,35 @@ func updateStar(c *gin.Context) {
 		return
 	}
 
-	var input models.Star
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный JSON: " + err.Error()})
+	input, err := bindStar(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := normalizeStarFrame(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
 	// Обновляем поля
 	existing.StarName = input.StarName
+	existing.Designation = input.Designation
 	existing.ShortDescription = input.ShortDescription
 	existing.Description = input.Description
 	existing.ImageURL = input.ImageURL
 	existing.IsActive = input.IsActive
 	existing.RA = input.RA
 	existing.Dec = input.Dec
+	existing.Epoch = input.Epoch
+	existing.Frame = input.Frame
+	existing.PMRA = input.PMRA
+	existing.PMDec = input.PMDec
+	existing.Parallax = input.Parallax
+	existing.RadialVelocity = input.RadialVelocity
+	existing.VMag = input.VMag
+	existing.BV = input.BV
+	existing.SpectralType = input.SpectralType
 
 	if err := db.Save(&existing).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при обновлении: " + err.Error()})
@@ -119,10 +273,63 @@ func updateStar(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Звезда успешно обновлена",
-		"star":    existing,
+		"star":    starJSON(c, existing),
 	})
 }
 
+// normalizeStarFrame подставляет эпоху и систему отсчёта по умолчанию и проверяет их
+func normalizeStarFrame(star *models.Star) error {
+	if star.Epoch == 0 {
+		star.Epoch = astro.DefaultEpoch
+	}
+	if star.Parallax < 0 {
+		return fmt.Errorf("Параллакс не может быть отрицательным")
+	}
+	star.SpectralType = strings.TrimSpace(star.SpectralType)
+	if star.Frame == "" {
+		star.Frame = astro.FrameICRS
+	}
+	if !astro.ValidFrame(star.Frame) {
+		return fmt.Errorf("Неподдерживаемая система отсчёта %q (допустимы %s, %s)", star.Frame, astro.FrameICRS, astro.FrameFK5)
+	}
+	return nil
+}
+
+// POST /api/stars/import?format=csv|bsc|hipparcos&dry_run=true
+// multipart/form-data: file — файл каталога
+// Звёзды сопоставляются по обозначению (designation): новые создаются, существующие обновляются
+func importStars(c *gin.Context) {
+	format := c.DefaultQuery("format", catalog.FormatCSV)
+	dryRun := c.Query("dry_run") == "true"
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Файл не получен"})
+		return
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка открытия файла: " + err.Error()})
+		return
+	}
+	defer src.Close()
+
+	rows, rowErrors, err := catalog.Parse(format, src)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Ошибка разбора каталога: " + err.Error()})
+		return
+	}
+
+	report, err := catalog.Import(repo, rows, rowErrors, dryRun)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка импорта: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
 func deleteStar(c *gin.Context) {
 	db := c.MustGet("db").(*gorm.DB)
 
@@ -213,16 +420,22 @@ func addStarToDraftOrder(c *gin.Context) {
 
 	// Проверяем, не добавлена ли уже звезда
 	var existing models.TelescopeObservationStar
-	if err := db.Where("observation_id = ? AND star_id = ?", order.TelescopeObservationID, starID).First(&existing).Error; err == nil {
+	if err := db.Where("telescope_observation_id = ? AND star_id = ?", order.TelescopeObservationID, starID).First(&existing).Error; err == nil {
 		c.JSON(http.StatusConflict, gin.H{"error": "Звезда уже добавлена в заявку"})
 		return
 	}
 
-	// Добавляем новую запись
+	orderNumber, err := repo.NextOrderNumber(order.TelescopeObservationID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка получения порядкового номера: " + err.Error()})
+		return
+	}
+
+	// Добавляем новую запись в конец очереди наблюдений
 	relation := models.TelescopeObservationStar{
 		TelescopeObservationID: order.TelescopeObservationID,
 		StarID:                 starID,
-		OrderNumber:            1,
+		OrderNumber:            orderNumber,
 		Quantity:               1,
 	}
 
@@ -0,0 +1,197 @@
+package api
+
+import (
+	"Lab1/internal/app/models"
+	"Lab1/internal/app/repository"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func InitTelescopeAPI(database *gorm.DB, r *gin.RouterGroup) {
+	db = database
+	repo = repository.NewRepositoryFromDB(db)
+	registerTelescopeRoutes(r)
+}
+
+func registerTelescopeRoutes(r *gin.RouterGroup) {
+	telescopes := r.Group("/telescopes")
+	{
+		telescopes.GET("", getTelescopes)
+		telescopes.GET("/:id", getTelescopeByID)
+		telescopes.POST("", createTelescope)
+		telescopes.PUT("/:id", updateTelescope)
+		telescopes.DELETE("/:id", deleteTelescope)
+	}
+}
+
+// validateTelescope проверяет параметры инструмента
+func validateTelescope(t *models.Telescope) error {
+	t.Name = strings.TrimSpace(t.Name)
+	if t.Name == "" {
+		return errors.New("Название телескопа обязательно")
+	}
+	if t.Aperture <= 0 {
+		return errors.New("Апертура должна быть положительной, мм")
+	}
+	if t.FocalLength < 0 || t.SlewRate < 0 || t.FieldOfView < 0 {
+		return errors.New("Фокусное расстояние, скорость наведения и поле зрения не могут быть отрицательными")
+	}
+	switch t.MountType {
+	case "":
+		t.MountType = models.MountEquatorial
+	case models.MountAltAz, models.MountEquatorial, models.MountFork:
+	default:
+		return fmt.Errorf("Тип монтировки должен быть %s, %s или %s", models.MountAltAz, models.MountEquatorial, models.MountFork)
+	}
+	return nil
+}
+
+// telescopeJSON дополняет профиль телескопа рассчитанными характеристиками
+func telescopeJSON(t models.Telescope) gin.H {
+	return gin.H{
+		"TelescopeID":       t.TelescopeID,
+		"Name":              t.Name,
+		"Aperture":          t.Aperture,
+		"FocalLength":       t.FocalLength,
+		"MountType":         t.MountType,
+		"SlewRate":          t.SlewRate,
+		"FieldOfView":       t.FieldOfView,
+		"LimitingMagnitude": t.LimitingMagnitude,
+		"FocalRatio":        t.FocalRatio(),
+		"MagnitudeLimit":    t.MagnitudeLimit(),
+	}
+}
+
+func getTelescopes(c *gin.Context) {
+	telescopes, err := repo.GetTelescopes()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка получения телескопов: " + err.Error()})
+		return
+	}
+
+	result := make([]gin.H, 0, len(telescopes))
+	for _, t := range telescopes {
+		result = append(result, telescopeJSON(t))
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func getTelescopeByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный ID"})
+		return
+	}
+
+	telescope, err := repo.GetTelescopeByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Телескоп не найден"})
+		return
+	}
+	c.JSON(http.StatusOK, telescopeJSON(*telescope))
+}
+
+func createTelescope(c *gin.Context) {
+	var input models.Telescope
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный JSON: " + err.Error()})
+		return
+	}
+	input.TelescopeID = 0
+
+	if err := validateTelescope(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := repo.CreateTelescope(&input); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка сохранения в БД: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":   "Телескоп добавлен",
+		"telescope": telescopeJSON(input),
+	})
+}
+
+func updateTelescope(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный ID"})
+		return
+	}
+
+	if _, err := repo.GetTelescopeByID(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Телескоп не найден"})
+		return
+	}
+
+	var input models.Telescope
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный JSON: " + err.Error()})
+		return
+	}
+
+	if err := validateTelescope(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = repo.UpdateTelescopeFields(id, map[string]interface{}{
+		"name":               input.Name,
+		"aperture":           input.Aperture,
+		"focal_length":       input.FocalLength,
+		"mount_type":         input.MountType,
+		"slew_rate":          input.SlewRate,
+		"field_of_view":      input.FieldOfView,
+		"limiting_magnitude": input.LimitingMagnitude,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при обновлении: " + err.Error()})
+		return
+	}
+
+	telescope, _ := repo.GetTelescopeByID(id)
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Телескоп обновлён",
+		"telescope": telescopeJSON(*telescope),
+	})
+}
+
+func deleteTelescope(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный ID"})
+		return
+	}
+
+	if _, err := repo.GetTelescopeByID(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Телескоп не найден"})
+		return
+	}
+
+	// Заявки хранят ссылку на телескоп, поэтому используемый телескоп удалить нельзя
+	count, err := repo.CountTelescopeOrders(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка проверки заявок: " + err.Error()})
+		return
+	}
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Телескоп используется в заявках: %d", count)})
+		return
+	}
+
+	if err := repo.DeleteTelescope(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при удалении телескопа"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Телескоп удалён"})
+}
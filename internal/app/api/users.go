@@ -2,11 +2,14 @@ package api
 
 import (
 	"Lab1/internal/app/auth"
+	"Lab1/internal/app/ical"
 	"Lab1/internal/app/models"
 	"Lab1/internal/app/repository"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
@@ -28,6 +31,7 @@ func registerUserRoutes(r *gin.RouterGroup) {
 		users.POST("/logout", logoutUser)
 		users.GET("/me", getCurrentUser)
 		users.PUT("/me", updateCurrentUser)
+		users.GET("/:id/observations.ics", getUserObservationsCalendar)
 	}
 }
 
@@ -128,3 +132,32 @@ func updateCurrentUser(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "user updated"})
 }
+
+// GET /api/users/:id/observations.ics
+// Календарь сформированных и завершённых заявок пользователя для подписки в календарных приложениях
+func getUserObservationsCalendar(c *gin.Context) {
+	uid, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	user, err := userRepo.GetUserByID(uid)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+
+	orders, err := userRepo.GetUserOrdersByStatuses(uid, []string{"сформирован", "завершён"})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", "text/calendar; charset=utf-8")
+	c.Header("Content-Disposition", `inline; filename="observations.ics"`)
+	c.Status(http.StatusOK)
+	if err := ical.WriteCalendar(c.Writer, "Наблюдения "+user.Username, orders); err != nil {
+		logrus.Error("Ошибка формирования календаря: ", err)
+	}
+}
@@ -0,0 +1,42 @@
+package astro
+
+import "math"
+
+// Постоянная годичной аберрации, угловые секунды
+const aberrationConstant = 20.49552
+
+// SunTrueLongitude возвращает истинную геометрическую долготу Солнца (градусы)
+// по формулам низкой точности (Meeus, гл. 25)
+func SunTrueLongitude(jd float64) float64 {
+	T := JulianCenturies(jd)
+
+	L0 := 280.46646 + 36000.76983*T + 0.0003032*T*T
+	M := degToRad(357.52911 + 35999.05029*T - 0.0001537*T*T)
+	C := (1.914602-0.004817*T-0.000014*T*T)*math.Sin(M) +
+		(0.019993-0.000101*T)*math.Sin(2*M) +
+		0.000289*math.Sin(3*M)
+
+	return normalizeDegrees(L0 + C)
+}
+
+// aberrate добавляет к координатам (градусы) годичную аберрацию (Meeus, формула 23.3)
+func aberrate(ra, dec, jd float64) (float64, float64) {
+	T := JulianCenturies(jd)
+
+	sun := degToRad(SunTrueLongitude(jd))
+	e := 0.016708634 - 0.000042037*T - 0.0000001267*T*T
+	pi := degToRad(102.93735 + 1.71946*T + 0.00046*T*T) // долгота перигелия
+	eps := degToRad(TrueObliquity(jd))
+	k := aberrationConstant / 3600
+
+	a := degToRad(ra)
+	d := degToRad(dec)
+
+	dRA := (-k*(math.Cos(a)*math.Cos(sun)*math.Cos(eps)+math.Sin(a)*math.Sin(sun)) +
+		e*k*(math.Cos(a)*math.Cos(pi)*math.Cos(eps)+math.Sin(a)*math.Sin(pi))) / math.Cos(d)
+
+	dDec := -k*(math.Cos(sun)*math.Cos(eps)*(math.Tan(eps)*math.Cos(d)-math.Sin(a)*math.Sin(d))+math.Cos(a)*math.Sin(d)*math.Sin(sun)) +
+		e*k*(math.Cos(pi)*math.Cos(eps)*(math.Tan(eps)*math.Cos(d)-math.Sin(a)*math.Sin(d))+math.Cos(a)*math.Sin(d)*math.Sin(pi))
+
+	return normalizeDegrees(ra + dRA), dec + dDec
+}
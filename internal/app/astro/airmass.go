@@ -0,0 +1,33 @@
+package astro
+
+import "math"
+
+// Типичный коэффициент атмосферной экстинкции в полосе V на уровне моря, звёздных величин на единицу воздушной массы
+const DefaultExtinctionCoefficient = 0.2
+
+// Шкала высот однородной атмосферы, метры
+const atmosphereScaleHeight = 8000
+
+// Airmass возвращает воздушную массу для высоты altitude (градусы) по модели Kasten–Young (1989).
+// Для звезды под горизонтом возвращает ok = false.
+func Airmass(altitude float64) (x float64, ok bool) {
+	if altitude <= 0 {
+		return 0, false
+	}
+
+	z := 90 - altitude // зенитное расстояние, градусы
+	x = 1 / (math.Cos(degToRad(z)) + 0.50572*math.Pow(96.07995-z, -1.6364))
+	return x, true
+}
+
+// Extinction возвращает ослабление блеска в звёздных величинах
+// для воздушной массы x и коэффициента экстинкции k
+func Extinction(x, k float64) float64 {
+	return k * x
+}
+
+// ExtinctionAtElevation пересчитывает коэффициент экстинкции k, заданный для уровня моря,
+// на высоту elevation метров: с высотой столб атмосферы над наблюдателем убывает экспоненциально
+func ExtinctionAtElevation(k, elevation float64) float64 {
+	return k * math.Exp(-elevation/atmosphereScaleHeight)
+}
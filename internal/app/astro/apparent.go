@@ -0,0 +1,35 @@
+package astro
+
+import "time"
+
+// Поддерживаемые системы отсчёта каталожных координат.
+// На точности расчётов приложения ICRS и FK5 совпадают.
+const (
+	FrameICRS = "ICRS"
+	FrameFK5  = "FK5"
+)
+
+// Эпоха каталога по умолчанию, юлианский год
+const DefaultEpoch = 2000.0
+
+// ValidFrame сообщает, поддерживается ли система отсчёта
+func ValidFrame(frame string) bool {
+	return frame == FrameICRS || frame == FrameFK5
+}
+
+// ApparentPlace переводит каталожные координаты (ra, dec в градусах) на эпоху
+// epoch (юлианский год) в видимые на момент t: прецессия к дате, нутация
+// и годичная аберрация
+func ApparentPlace(ra, dec, epoch float64, t time.Time) (float64, float64) {
+	if epoch == 0 {
+		epoch = DefaultEpoch
+	}
+
+	jd := JulianDate(t)
+
+	ra, dec = Precess(ra, dec, JulianEpochToJD(epoch), jd)
+	ra, dec = nutate(ra, dec, jd)
+	ra, dec = aberrate(ra, dec, jd)
+
+	return ra, dec
+}
@@ -0,0 +1,144 @@
+package astro
+
+import (
+	"sort"
+	"time"
+)
+
+// Высота центра Луны в момент восхода/захода с учётом рефракции и полудиаметра,
+// для топоцентрических координат, градусы
+const MoonriseAltitude = -0.8333
+
+// Interval — промежуток времени
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Duration возвращает длительность промежутка
+func (i Interval) Duration() time.Duration {
+	return i.End.Sub(i.Start)
+}
+
+// BelowIntervals возвращает промежутки внутри [from, to], когда функция altitude ниже h0
+func BelowIntervals(altitude func(time.Time) float64, from, to time.Time, h0 float64) []Interval {
+	var intervals []Interval
+
+	var start *time.Time
+	if altitude(from) < h0 {
+		start = &from
+	}
+	for _, c := range FindCrossings(altitude, from, to, h0) {
+		c := c
+		if !c.Rising {
+			start = &c.Time
+		} else if start != nil {
+			intervals = append(intervals, Interval{Start: *start, End: c.Time})
+			start = nil
+		}
+	}
+	if start != nil {
+		intervals = append(intervals, Interval{Start: *start, End: to})
+	}
+	return intervals
+}
+
+// IntersectIntervals возвращает пересечение двух наборов непересекающихся промежутков
+func IntersectIntervals(a, b []Interval) []Interval {
+	var result []Interval
+	for _, x := range a {
+		for _, y := range b {
+			start, end := x.Start, x.End
+			if y.Start.After(start) {
+				start = y.Start
+			}
+			if y.End.Before(end) {
+				end = y.End
+			}
+			if end.After(start) {
+				result = append(result, Interval{Start: start, End: end})
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Start.Before(result[j].Start) })
+	return result
+}
+
+// DarkTime — сумерки, восход и заход Луны и полностью тёмное время одной ночи
+type DarkTime struct {
+	Night
+
+	MoonRise         *time.Time // первый восход Луны за ночь
+	MoonSet          *time.Time // первый заход Луны за ночь
+	MoonIllumination float64    // освещённость Луны в середине ночи
+	MoonPhase        string
+
+	// Промежутки, когда Солнце ниже −18° и Луна под горизонтом
+	DarkIntervals []Interval
+	DarkHours     float64
+}
+
+// DarkTimeFrom рассчитывает тёмное время на сутках, начинающихся в местный средний полдень start
+func DarkTimeFrom(obs Observer, start time.Time) DarkTime {
+	night := NightFrom(obs, start)
+	dark := DarkTime{Night: night}
+
+	moonAltitude := func(t time.Time) float64 {
+		ra, dec := MoonTopocentric(obs, t)
+		return EquatorialToHorizontal(ra, dec, obs, t).Altitude
+	}
+	sunAltitude := func(t time.Time) float64 {
+		return SunHorizontal(obs, t).Altitude
+	}
+
+	for _, c := range FindCrossings(moonAltitude, night.Start, night.End, MoonriseAltitude) {
+		c := c
+		if c.Rising && dark.MoonRise == nil {
+			dark.MoonRise = &c.Time
+		}
+		if !c.Rising && dark.MoonSet == nil {
+			dark.MoonSet = &c.Time
+		}
+	}
+
+	// Фаза берётся в середине ночи: между концом и началом астрономических сумерек,
+	// а если их нет — в местную полночь
+	middle := night.Start.Add(12 * time.Hour)
+	if night.AstronomicalDusk != nil && night.AstronomicalDawn != nil {
+		middle = night.AstronomicalDusk.Add(night.AstronomicalDawn.Sub(*night.AstronomicalDusk) / 2)
+	}
+	phase := MoonPhaseAt(middle)
+	dark.MoonIllumination = phase.Illumination
+	dark.MoonPhase = phase.Name
+
+	dark.DarkIntervals = IntersectIntervals(
+		BelowIntervals(sunAltitude, night.Start, night.End, AstronomicalTwilightAltitude),
+		BelowIntervals(moonAltitude, night.Start, night.End, MoonriseAltitude),
+	)
+	var total time.Duration
+	for _, i := range dark.DarkIntervals {
+		total += i.Duration()
+	}
+	dark.DarkHours = total.Hours()
+
+	return dark
+}
+
+// In возвращает моменты тёмного времени в часовом поясе loc
+func (d DarkTime) In(loc *time.Location) DarkTime {
+	d.Night = d.Night.In(loc)
+	if d.MoonRise != nil {
+		rise := d.MoonRise.In(loc)
+		d.MoonRise = &rise
+	}
+	if d.MoonSet != nil {
+		set := d.MoonSet.In(loc)
+		d.MoonSet = &set
+	}
+	intervals := make([]Interval, len(d.DarkIntervals))
+	for i, interval := range d.DarkIntervals {
+		intervals[i] = Interval{Start: interval.Start.In(loc), End: interval.End.In(loc)}
+	}
+	d.DarkIntervals = intervals
+	return d
+}
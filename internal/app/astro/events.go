@@ -0,0 +1,52 @@
+package astro
+
+import "time"
+
+// Шаг поиска пересечений высоты и точность их уточнения
+const (
+	crossingStep      = 10 * time.Minute
+	crossingPrecision = time.Second
+)
+
+// Crossing — момент, когда светило пересекает заданную высоту
+type Crossing struct {
+	Time   time.Time
+	Rising bool // светило поднимается выше заданной высоты
+}
+
+// FindCrossings ищет на интервале [from, to] моменты пересечения высоты h0 функцией altitude.
+// Интервал просматривается с шагом 10 минут, найденные пересечения уточняются делением пополам.
+// Подходит для Солнца и Луны, координаты которых заметно меняются за сутки.
+func FindCrossings(altitude func(time.Time) float64, from, to time.Time, h0 float64) []Crossing {
+	var crossings []Crossing
+
+	prevT := from
+	prev := altitude(from) - h0
+	for t := from.Add(crossingStep); !prevT.Equal(to); t = t.Add(crossingStep) {
+		if t.After(to) {
+			t = to
+		}
+		cur := altitude(t) - h0
+		if (prev < 0) != (cur < 0) {
+			crossings = append(crossings, Crossing{
+				Time:   bisect(altitude, prevT, t, h0, prev < 0),
+				Rising: prev < 0,
+			})
+		}
+		prevT, prev = t, cur
+	}
+	return crossings
+}
+
+// bisect уточняет момент пересечения высоты h0 между lo и hi
+func bisect(altitude func(time.Time) float64, lo, hi time.Time, h0 float64, rising bool) time.Time {
+	for hi.Sub(lo) > crossingPrecision {
+		mid := lo.Add(hi.Sub(lo) / 2)
+		if (altitude(mid) < h0) == rising {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return lo.Add(hi.Sub(lo) / 2)
+}
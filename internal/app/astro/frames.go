@@ -0,0 +1,95 @@
+package astro
+
+import "math"
+
+// Наклон эклиптики эпохи J2000.0, градусы
+const ObliquityJ2000 = 23.4392911
+
+// Матрица поворота ICRS → галактическая система (Hipparcos, т. 1, §1.5.3)
+var equatorialToGalactic = [3][3]float64{
+	{-0.0548755604162154, -0.8734370902348850, -0.4838350155487132},
+	{+0.4941094278755837, -0.4448296299600112, +0.7469822444972189},
+	{-0.8676661490190047, -0.1980763734312015, +0.4559837761750669},
+}
+
+// Galactic — галактические координаты, градусы
+type Galactic struct {
+	L float64 // долгота [0, 360)
+	B float64 // широта [-90, 90]
+}
+
+// Ecliptic — эклиптические координаты, градусы
+type Ecliptic struct {
+	Lon float64 // долгота [0, 360)
+	Lat float64 // широта [-90, 90]
+}
+
+// EquatorialToGalactic переводит координаты ICRS (градусы) в галактические
+func EquatorialToGalactic(ra, dec float64) Galactic {
+	v := rotate(equatorialToGalactic, sphericalToVector(ra, dec))
+	l, b := vectorToSpherical(v)
+	return Galactic{L: l, B: b}
+}
+
+// GalacticToEquatorial переводит галактические координаты (градусы) в ICRS
+func GalacticToEquatorial(g Galactic) (ra, dec float64) {
+	v := rotate(transpose(equatorialToGalactic), sphericalToVector(g.L, g.B))
+	return vectorToSpherical(v)
+}
+
+// EquatorialToEcliptic переводит экваториальные координаты (градусы)
+// в эклиптические при наклоне эклиптики eps (градусы)
+func EquatorialToEcliptic(ra, dec, eps float64) Ecliptic {
+	a := degToRad(ra)
+	d := degToRad(dec)
+	e := degToRad(eps)
+
+	lon := math.Atan2(math.Sin(a)*math.Cos(e)+math.Tan(d)*math.Sin(e), math.Cos(a))
+	lat := math.Asin(clamp(math.Sin(d)*math.Cos(e)-math.Cos(d)*math.Sin(e)*math.Sin(a), -1, 1))
+
+	return Ecliptic{Lon: normalizeDegrees(radToDeg(lon)), Lat: radToDeg(lat)}
+}
+
+// EclipticToEquatorial переводит эклиптические координаты (градусы)
+// в экваториальные при наклоне эклиптики eps (градусы)
+func EclipticToEquatorial(ecl Ecliptic, eps float64) (ra, dec float64) {
+	l := degToRad(ecl.Lon)
+	b := degToRad(ecl.Lat)
+	e := degToRad(eps)
+
+	a := math.Atan2(math.Sin(l)*math.Cos(e)-math.Tan(b)*math.Sin(e), math.Cos(l))
+	d := math.Asin(clamp(math.Sin(b)*math.Cos(e)+math.Cos(b)*math.Sin(e)*math.Sin(l), -1, 1))
+
+	return normalizeDegrees(radToDeg(a)), radToDeg(d)
+}
+
+func sphericalToVector(lon, lat float64) [3]float64 {
+	l := degToRad(lon)
+	b := degToRad(lat)
+	return [3]float64{math.Cos(b) * math.Cos(l), math.Cos(b) * math.Sin(l), math.Sin(b)}
+}
+
+func vectorToSpherical(v [3]float64) (lon, lat float64) {
+	r := math.Sqrt(v[0]*v[0] + v[1]*v[1] + v[2]*v[2])
+	lon = normalizeDegrees(radToDeg(math.Atan2(v[1], v[0])))
+	lat = radToDeg(math.Asin(clamp(v[2]/r, -1, 1)))
+	return lon, lat
+}
+
+func rotate(m [3][3]float64, v [3]float64) [3]float64 {
+	var out [3]float64
+	for i := range out {
+		out[i] = m[i][0]*v[0] + m[i][1]*v[1] + m[i][2]*v[2]
+	}
+	return out
+}
+
+func transpose(m [3][3]float64) [3][3]float64 {
+	var t [3][3]float64
+	for i := range m {
+		for j := range m[i] {
+			t[j][i] = m[i][j]
+		}
+	}
+	return t
+}
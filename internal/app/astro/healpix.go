@@ -0,0 +1,167 @@
+package astro
+
+import "math"
+
+// Разбиение сферы HEALPix (Górski et al., 2005) в схеме NESTED.
+// Пиксель порядка order имеет nside = 2^order; у пикселя p порядка k четыре потомка
+// 4p..4p+3 порядка k+1, поэтому пиксель грубого порядка соответствует непрерывному
+// диапазону номеров пикселей мелкого порядка.
+
+// Наибольший радиус пикселя при nside = 1 — 0.841 рад, с ростом nside он убывает
+// не медленнее 1.03/nside. Запас с коэффициентом около двух гарантирует,
+// что пиксель, центр которого дальше radius + healpixMargin/nside, не пересекает круг.
+const healpixMargin = 1.7
+
+// Номер базового пикселя по строке и столбцу
+var (
+	healpixJRLL = [12]int64{2, 2, 2, 2, 3, 3, 3, 3, 4, 4, 4, 4}
+	healpixJPLL = [12]int64{1, 3, 5, 7, 0, 2, 4, 6, 1, 3, 5, 7}
+)
+
+// PixelRange — диапазон номеров пикселей [First, Last]
+type PixelRange struct {
+	First int64
+	Last  int64
+}
+
+// HEALPixNest возвращает номер пикселя NESTED порядка order, содержащего точку (ra, dec) в градусах
+func HEALPixNest(order int, ra, dec float64) int64 {
+	nside := int64(1) << order
+	z := math.Sin(degToRad(dec))
+	za := math.Abs(z)
+	tt := math.Mod(degToRad(ra), 2*math.Pi)
+	if tt < 0 {
+		tt += 2 * math.Pi
+	}
+	tt /= math.Pi / 2 // 0..4
+
+	var face, ix, iy int64
+	if za <= 2.0/3 {
+		// Экваториальная зона
+		t1 := float64(nside) * (0.5 + tt)
+		t2 := float64(nside) * z * 0.75
+		jp := int64(t1 - t2)
+		jm := int64(t1 + t2)
+		ifp := jp >> order
+		ifm := jm >> order
+		switch {
+		case ifp == ifm:
+			face = ifp | 4
+		case ifp < ifm:
+			face = ifp
+		default:
+			face = ifm + 8
+		}
+		ix = jm & (nside - 1)
+		iy = nside - (jp & (nside - 1)) - 1
+	} else {
+		// Полярные шапки
+		ntt := min(int64(tt), 3)
+		tp := tt - float64(ntt)
+		tmp := float64(nside) * math.Sqrt(3*(1-za))
+		jp := min(int64(tp*tmp), nside-1)
+		jm := min(int64((1-tp)*tmp), nside-1)
+		if z >= 0 {
+			face = ntt
+			ix, iy = nside-jm-1, nside-jp-1
+		} else {
+			face = ntt + 8
+			ix, iy = jp, jm
+		}
+	}
+	return face<<(2*order) + spreadBits(ix) + spreadBits(iy)<<1
+}
+
+// HEALPixCenter возвращает центр пикселя NESTED порядка order (ra, dec в градусах)
+func HEALPixCenter(order int, pix int64) (ra, dec float64) {
+	nside := int64(1) << order
+	npix := 12 * nside * nside
+	face := pix >> (2 * order)
+	ipf := pix & (nside*nside - 1)
+	ix, iy := compressBits(ipf), compressBits(ipf>>1)
+
+	jr := healpixJRLL[face]*nside - ix - iy - 1
+	var nr, kshift int64
+	var z float64
+	switch {
+	case jr < nside:
+		nr = jr
+		z = 1 - float64(nr*nr)*4/float64(npix)
+	case jr > 3*nside:
+		nr = 4*nside - jr
+		z = float64(nr*nr)*4/float64(npix) - 1
+	default:
+		nr = nside
+		z = float64(2*nside-jr) * 8 * float64(nside) / float64(npix)
+		kshift = (jr - nside) & 1
+	}
+
+	jp := (healpixJPLL[face]*nr + ix - iy + 1 + kshift) / 2
+	if jp > 4*nside {
+		jp -= 4 * nside
+	}
+	if jp < 1 {
+		jp += 4 * nside
+	}
+	phi := (float64(jp) - float64(kshift+1)*0.5) * (math.Pi / 2 / float64(nr))
+	return radToDeg(phi), radToDeg(math.Asin(z))
+}
+
+// HEALPixDisc возвращает диапазоны номеров пикселей порядка order, покрывающие круг
+// радиуса radius градусов вокруг (ra, dec). Покрытие избыточно: пиксели диапазонов
+// могут лежать вне круга, но ни одна точка круга не пропускается.
+func HEALPixDisc(order int, ra, dec, radius float64) []PixelRange {
+	// Спуск идёт до порядка, на котором пиксель примерно вчетверо меньше радиуса
+	stop := order
+	if r := degToRad(radius); r > 0 {
+		stop = min(order, max(0, int(math.Floor(math.Log2(4/r)))))
+	}
+
+	var ranges []PixelRange
+	var visit func(level int, pix int64)
+	visit = func(level int, pix int64) {
+		cra, cdec := HEALPixCenter(level, pix)
+		limit := radius + radToDeg(healpixMargin/float64(int64(1)<<level))
+		if AngularSeparation(ra, dec, cra, cdec) > limit {
+			return
+		}
+		if level < stop {
+			for child := pix * 4; child < pix*4+4; child++ {
+				visit(level+1, child)
+			}
+			return
+		}
+
+		shift := 2 * (order - level)
+		r := PixelRange{First: pix << shift, Last: (pix+1)<<shift - 1}
+		if n := len(ranges); n > 0 && ranges[n-1].Last+1 == r.First {
+			ranges[n-1].Last = r.Last
+			return
+		}
+		ranges = append(ranges, r)
+	}
+	for face := int64(0); face < 12; face++ {
+		visit(0, face)
+	}
+	return ranges
+}
+
+// spreadBits раздвигает биты x в чётные разряды
+func spreadBits(x int64) int64 {
+	var r int64
+	for i := 0; x != 0; i++ {
+		r |= (x & 1) << (2 * i)
+		x >>= 1
+	}
+	return r
+}
+
+// compressBits собирает чётные разряды x
+func compressBits(x int64) int64 {
+	var r int64
+	for i := 0; x != 0; i++ {
+		r |= (x & 1) << i
+		x >>= 2
+	}
+	return r
+}
@@ -0,0 +1,87 @@
+package astro
+
+import (
+	"math"
+	"time"
+)
+
+// Observer — место наблюдения на поверхности Земли
+type Observer struct {
+	Latitude     float64 // широта, градусы (север — положительная)
+	Longitude    float64 // долгота, градусы (восток — положительная)
+	Elevation    float64 // высота над уровнем моря, метры
+	HorizonLimit float64 // минимальная доступная высота наведения, градусы
+}
+
+// HorizonAltitude возвращает высоту, на которой звезда считается взошедшей:
+// стандартная высота с учётом понижения горизонта для места над уровнем моря,
+// либо ограничение по горизонту инструмента, если оно задано
+func (o Observer) HorizonAltitude() float64 {
+	if o.HorizonLimit > 0 {
+		return o.HorizonLimit
+	}
+	return StandardAltitude - HorizonDip(o.Elevation)
+}
+
+// ExtinctionCoefficient возвращает коэффициент экстинкции для высоты места наблюдения
+func (o Observer) ExtinctionCoefficient() float64 {
+	return ExtinctionAtElevation(DefaultExtinctionCoefficient, o.Elevation)
+}
+
+// HorizonDip возвращает понижение видимого горизонта (градусы) для наблюдателя
+// на высоте elevation метров: 1.76′·√h
+func HorizonDip(elevation float64) float64 {
+	if elevation <= 0 {
+		return 0
+	}
+	return 1.76 / 60 * math.Sqrt(elevation)
+}
+
+// Horizontal — горизонтальные координаты звезды для наблюдателя в заданный момент
+type Horizontal struct {
+	Altitude          float64 // высота над горизонтом, градусы
+	Azimuth           float64 // азимут от севера через восток, градусы [0, 360)
+	HourAngle         float64 // часовой угол, часы [-12, 12)
+	LocalSiderealTime float64 // местное звёздное время, часы [0, 24)
+}
+
+// EquatorialToHorizontal переводит экваториальные координаты (ra, dec в градусах)
+// в горизонтальные для наблюдателя obs в момент t
+func EquatorialToHorizontal(ra, dec float64, obs Observer, t time.Time) Horizontal {
+	lst := LocalSiderealTime(t, obs.Longitude)
+
+	ha := lst - ra/15.0
+	ha = normalizeHours(ha+12) - 12
+
+	H := degToRad(ha * 15)
+	d := degToRad(dec)
+	phi := degToRad(obs.Latitude)
+
+	sinAlt := math.Sin(phi)*math.Sin(d) + math.Cos(phi)*math.Cos(d)*math.Cos(H)
+	alt := math.Asin(clamp(sinAlt, -1, 1))
+
+	// Азимут отсчитывается от севера через восток
+	az := math.Atan2(
+		-math.Cos(d)*math.Sin(H),
+		math.Sin(d)*math.Cos(phi)-math.Cos(d)*math.Cos(H)*math.Sin(phi),
+	)
+
+	return Horizontal{
+		Altitude:          radToDeg(alt),
+		Azimuth:           normalizeDegrees(radToDeg(az)),
+		HourAngle:         ha,
+		LocalSiderealTime: lst,
+	}
+}
+
+func degToRad(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func radToDeg(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
+
+func clamp(x, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, x))
+}
@@ -0,0 +1,192 @@
+package astro
+
+import (
+	"math"
+	"time"
+)
+
+// Экваториальный радиус Земли, км
+const earthRadius = 6378.14
+
+// Километров в астрономической единице
+const kmPerAU = 149597870.7
+
+// moonTerm — периодический член ряда для Луны: кратности аргументов D, M, M′, F и коэффициент
+type moonTerm struct {
+	D, M, Mp, F float64
+	coeff       float64
+}
+
+// Главные члены рядов для долготы (10⁻⁶ градуса) и расстояния (10⁻³ км), Meeus, табл. 47.A.
+// Отброшены члены меньше 2″ по долготе и 2 км по расстоянию.
+var moonLongitudeTerms = []moonTerm{
+	{0, 0, 1, 0, 6288774}, {2, 0, -1, 0, 1274027}, {2, 0, 0, 0, 658314}, {0, 0, 2, 0, 213618},
+	{0, 1, 0, 0, -185116}, {0, 0, 0, 2, -114332}, {2, 0, -2, 0, 58793}, {2, -1, -1, 0, 57066},
+	{2, 0, 1, 0, 53322}, {2, -1, 0, 0, 45758}, {0, 1, -1, 0, -40923}, {1, 0, 0, 0, -34720},
+	{0, 1, 1, 0, -30383}, {2, 0, 0, -2, 15327}, {0, 0, 1, 2, -12528}, {0, 0, 1, -2, 10980},
+	{4, 0, -1, 0, 10675}, {0, 0, 3, 0, 10034}, {4, 0, -2, 0, 8548}, {2, 1, -1, 0, -7888},
+	{2, 1, 0, 0, -6766}, {1, 0, -1, 0, -5163}, {1, 1, 0, 0, 4987}, {2, -1, 1, 0, 4036},
+	{2, 0, 2, 0, 3994}, {4, 0, 0, 0, 3861}, {2, 0, -3, 0, 3665}, {0, 1, -2, 0, -2689},
+	{2, 0, -1, 2, -2602}, {2, -1, -2, 0, 2390}, {1, 0, 1, 0, -2348}, {2, -2, 0, 0, 2236},
+	{0, 1, 2, 0, -2120}, {0, 2, 0, 0, -2069},
+}
+
+var moonDistanceTerms = []moonTerm{
+	{0, 0, 1, 0, -20905355}, {2, 0, -1, 0, -3699111}, {2, 0, 0, 0, -2955968}, {0, 0, 2, 0, -569925},
+	{0, 1, 0, 0, 48888}, {0, 0, 0, 2, -3149}, {2, 0, -2, 0, 246158}, {2, -1, -1, 0, -152138},
+	{2, 0, 1, 0, -170733}, {2, -1, 0, 0, -204586}, {0, 1, -1, 0, -129620}, {1, 0, 0, 0, 108743},
+	{0, 1, 1, 0, 104755}, {2, 0, 0, -2, 10321}, {0, 0, 1, -2, 79661}, {4, 0, -1, 0, -34782},
+	{0, 0, 3, 0, -23210}, {4, 0, -2, 0, -21636}, {2, 1, -1, 0, 24208}, {2, 1, 0, 0, 30824},
+	{1, 0, -1, 0, -8379}, {1, 1, 0, 0, -16675}, {2, -1, 1, 0, -12831}, {2, 0, 2, 0, -10445},
+	{4, 0, 0, 0, -11650}, {2, 0, -3, 0, 14403}, {0, 1, -2, 0, -7003}, {2, -1, -2, 0, 10056},
+	{1, 0, 1, 0, 6322}, {2, -2, 0, 0, -9884}, {0, 1, 2, 0, 5751},
+}
+
+// Главные члены ряда для широты (10⁻⁶ градуса), Meeus, табл. 47.B
+var moonLatitudeTerms = []moonTerm{
+	{0, 0, 0, 1, 5128122}, {0, 0, 1, 1, 280602}, {0, 0, 1, -1, 277693}, {2, 0, 0, -1, 173237},
+	{2, 0, -1, 1, 55413}, {2, 0, -1, -1, 46271}, {2, 0, 0, 1, 32573}, {0, 0, 2, 1, 17198},
+	{2, 0, 1, -1, 9266}, {0, 0, 2, -1, 8822}, {2, -1, 0, -1, 8216}, {2, 0, -2, -1, 4324},
+	{2, 0, 1, 1, 4200}, {2, 1, 0, -1, -3359}, {2, -1, -1, 1, 2463}, {2, -1, 0, 1, 2211},
+	{2, -1, -1, -1, 2065}, {0, 1, -1, -1, -1870},
+}
+
+// MoonPosition возвращает видимые геоцентрические координаты Луны (градусы)
+// и расстояние до её центра в километрах. Точность около 10″ (Meeus, гл. 47).
+func MoonPosition(t time.Time) (ra, dec, distance float64) {
+	jd := JulianDate(t)
+	T := JulianCenturies(jd)
+
+	Lp := 218.3164477 + 481267.88123421*T - 0.0015786*T*T + T*T*T/538841 - T*T*T*T/65194000
+	D := 297.8501921 + 445267.1114034*T - 0.0018819*T*T + T*T*T/545868 - T*T*T*T/113065000
+	M := 357.5291092 + 35999.0502909*T - 0.0001536*T*T + T*T*T/24490000
+	Mp := 134.9633964 + 477198.8675055*T + 0.0087414*T*T + T*T*T/69699 - T*T*T*T/14712000
+	F := 93.2720950 + 483202.0175233*T - 0.0036539*T*T - T*T*T/3526000 + T*T*T*T/863310000
+	E := 1 - 0.002516*T - 0.0000074*T*T
+
+	A1 := 119.75 + 131.849*T
+	A2 := 53.09 + 479264.290*T
+	A3 := 313.45 + 481266.484*T
+
+	// arg возвращает аргумент члена и множитель эксцентриситета орбиты Земли
+	arg := func(term moonTerm) (float64, float64) {
+		x := degToRad(term.D*D + term.M*M + term.Mp*Mp + term.F*F)
+		return x, math.Pow(E, math.Abs(term.M))
+	}
+
+	var sumL, sumR, sumB float64
+	for _, term := range moonLongitudeTerms {
+		x, e := arg(term)
+		sumL += term.coeff * e * math.Sin(x)
+	}
+	for _, term := range moonDistanceTerms {
+		x, e := arg(term)
+		sumR += term.coeff * e * math.Cos(x)
+	}
+	for _, term := range moonLatitudeTerms {
+		x, e := arg(term)
+		sumB += term.coeff * e * math.Sin(x)
+	}
+
+	// Поправки за действие Венеры, Юпитера и сжатие Земли
+	sumL += 3958*math.Sin(degToRad(A1)) + 1962*math.Sin(degToRad(Lp-F)) + 318*math.Sin(degToRad(A2))
+	sumB += -2235*math.Sin(degToRad(Lp)) + 382*math.Sin(degToRad(A3)) +
+		175*math.Sin(degToRad(A1-F)) + 175*math.Sin(degToRad(A1+F)) +
+		127*math.Sin(degToRad(Lp-Mp)) - 115*math.Sin(degToRad(Lp+Mp))
+
+	dPsi, _ := Nutation(jd)
+	ecl := Ecliptic{
+		Lon: normalizeDegrees(Lp + sumL/1e6 + dPsi),
+		Lat: sumB / 1e6,
+	}
+	distance = 385000.56 + sumR/1000
+
+	ra, dec = EclipticToEquatorial(ecl, TrueObliquity(jd))
+	return ra, dec, distance
+}
+
+// Topocentric переводит геоцентрические координаты близкого тела (градусы, расстояние в км)
+// в топоцентрические для наблюдателя obs с учётом суточного параллакса (Meeus, гл. 11, 40)
+func Topocentric(ra, dec, distance float64, obs Observer, t time.Time) (float64, float64) {
+	const flattening = 1 / 298.257
+	phi := degToRad(obs.Latitude)
+
+	u := math.Atan((1 - flattening) * math.Tan(phi))
+	rhoSin := (1-flattening)*math.Sin(u) + obs.Elevation/(earthRadius*1000)*math.Sin(phi)
+	rhoCos := math.Cos(u) + obs.Elevation/(earthRadius*1000)*math.Cos(phi)
+
+	sinPi := earthRadius / distance
+	H := degToRad(LocalSiderealTime(t, obs.Longitude)*15 - ra)
+	d := degToRad(dec)
+
+	dA := math.Atan2(-rhoCos*sinPi*math.Sin(H), math.Cos(d)-rhoCos*sinPi*math.Cos(H))
+	decTopo := math.Atan2((math.Sin(d)-rhoSin*sinPi)*math.Cos(dA), math.Cos(d)-rhoCos*sinPi*math.Cos(H))
+
+	return normalizeDegrees(ra + radToDeg(dA)), radToDeg(decTopo)
+}
+
+// MoonTopocentric возвращает координаты Луны (градусы), видимые наблюдателем obs
+func MoonTopocentric(obs Observer, t time.Time) (ra, dec float64) {
+	ra, dec, distance := MoonPosition(t)
+	return Topocentric(ra, dec, distance, obs, t)
+}
+
+// MoonPhase — освещённость Луны
+type MoonPhase struct {
+	Illumination float64 // освещённая доля диска [0, 1]
+	PhaseAngle   float64 // фазовый угол, градусы: 0 — полнолуние, 180 — новолуние
+	Elongation   float64 // угловое расстояние от Солнца, градусы
+	Waxing       bool    // растущая Луна
+	Name         string
+}
+
+// MoonPhaseAt рассчитывает фазу Луны в момент t (Meeus, гл. 48)
+func MoonPhaseAt(t time.Time) MoonPhase {
+	moonRA, moonDec, moonDistance := MoonPosition(t)
+	sunRA, sunDec, sunDistance := SunPosition(t)
+
+	psi := AngularSeparation(sunRA, sunDec, moonRA, moonDec)
+	R := sunDistance * kmPerAU
+	i := math.Atan2(R*math.Sin(degToRad(psi)), moonDistance-R*math.Cos(degToRad(psi)))
+
+	// Луна растёт, пока её долгота опережает солнечную менее чем на 180°
+	jd := JulianDate(t)
+	eps := TrueObliquity(jd)
+	moonLon := EquatorialToEcliptic(moonRA, moonDec, eps).Lon
+	sunLon := EquatorialToEcliptic(sunRA, sunDec, eps).Lon
+	waxing := normalizeDegrees(moonLon-sunLon) < 180
+
+	phase := MoonPhase{
+		Illumination: (1 + math.Cos(i)) / 2,
+		PhaseAngle:   radToDeg(i),
+		Elongation:   psi,
+		Waxing:       waxing,
+	}
+	phase.Name = moonPhaseName(phase.Illumination, waxing)
+	return phase
+}
+
+// moonPhaseName возвращает название фазы по освещённости
+func moonPhaseName(illumination float64, waxing bool) string {
+	switch {
+	case illumination < 0.02:
+		return "новолуние"
+	case illumination > 0.98:
+		return "полнолуние"
+	case math.Abs(illumination-0.5) < 0.05:
+		if waxing {
+			return "первая четверть"
+		}
+		return "последняя четверть"
+	case illumination < 0.5:
+		if waxing {
+			return "растущий серп"
+		}
+		return "убывающий серп"
+	default:
+		if waxing {
+			return "растущая Луна"
+		}
+		return "убывающая Луна"
+	}
+}
@@ -0,0 +1,62 @@
+package astro
+
+import (
+	"math"
+	"time"
+)
+
+// Перевод км/с в парсеки в юлианский год
+const kmsToParsecPerYear = 1.0227121650537077e-6
+
+// Motion — собственное движение и пространственная скорость звезды
+type Motion struct {
+	PMRA           float64 // собственное движение по прямому восхождению μα·cos δ, мсд/год
+	PMDec          float64 // собственное движение по склонению, мсд/год
+	Parallax       float64 // параллакс, мсд
+	RadialVelocity float64 // лучевая скорость, км/с (положительная — удаление)
+}
+
+// Propagate переносит положение звезды (ra, dec в градусах) с эпохи epoch
+// (юлианский год) на момент t с учётом пространственного движения.
+// Без известного параллакса лучевая скорость не учитывается.
+func Propagate(ra, dec float64, m Motion, epoch float64, t time.Time) (float64, float64) {
+	if m.PMRA == 0 && m.PMDec == 0 && m.RadialVelocity == 0 {
+		return ra, dec
+	}
+	if epoch == 0 {
+		epoch = DefaultEpoch
+	}
+
+	years := (JulianDate(t) - JulianEpochToJD(epoch)) / 365.25
+
+	a := degToRad(ra)
+	d := degToRad(dec)
+
+	// Единичный вектор на звезду и орты в направлениях роста α и δ
+	p := [3]float64{math.Cos(d) * math.Cos(a), math.Cos(d) * math.Sin(a), math.Sin(d)}
+	eA := [3]float64{-math.Sin(a), math.Cos(a), 0}
+	eD := [3]float64{-math.Sin(d) * math.Cos(a), -math.Sin(d) * math.Sin(a), math.Cos(d)}
+
+	// Расстояние в парсеках; без параллакса берём единицу — направление от этого не зависит
+	dist := 1.0
+	vr := 0.0
+	if m.Parallax > 0 {
+		dist = 1000 / m.Parallax
+		vr = m.RadialVelocity * kmsToParsecPerYear
+	}
+
+	muA := arcsecToRad(m.PMRA / 1000)
+	muD := arcsecToRad(m.PMDec / 1000)
+
+	var pos [3]float64
+	for i := range pos {
+		v := dist*(muA*eA[i]+muD*eD[i]) + vr*p[i]
+		pos[i] = dist*p[i] + v*years
+	}
+
+	r := math.Sqrt(pos[0]*pos[0] + pos[1]*pos[1] + pos[2]*pos[2])
+	newRA := normalizeDegrees(radToDeg(math.Atan2(pos[1], pos[0])))
+	newDec := radToDeg(math.Asin(clamp(pos[2]/r, -1, 1)))
+
+	return newRA, newDec
+}
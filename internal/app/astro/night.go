@@ -0,0 +1,102 @@
+package astro
+
+import "time"
+
+// Night — вечерние и утренние сумерки одной ночи. Поле равно nil, если
+// Солнце в эту ночь не опускается до соответствующей высоты (белые ночи, полярный день)
+// или не поднимается до неё (полярная ночь).
+type Night struct {
+	Start time.Time // местный средний полдень, с которого начинается ночь
+	End   time.Time // местный средний полдень следующих суток
+
+	Sunset           *time.Time
+	CivilDusk        *time.Time
+	NauticalDusk     *time.Time
+	AstronomicalDusk *time.Time
+	AstronomicalDawn *time.Time
+	NauticalDawn     *time.Time
+	CivilDawn        *time.Time
+	Sunrise          *time.Time
+}
+
+// LocalNoon возвращает местный средний полдень календарной даты
+// для наблюдателя obs: 12ч UT минус долгота в часах
+func LocalNoon(obs Observer, year int, month time.Month, day int) time.Time {
+	noonOffset := time.Duration((12 - obs.Longitude/15) * float64(time.Hour))
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Add(noonOffset)
+}
+
+// NightAt возвращает ночь, которой принадлежит момент t: интервал между
+// местными средними полуднями, содержащий t
+func NightAt(obs Observer, t time.Time) Night {
+	utc := t.UTC()
+	start := LocalNoon(obs, utc.Year(), utc.Month(), utc.Day())
+	for start.After(t) {
+		start = start.Add(-24 * time.Hour)
+	}
+	for !start.Add(24 * time.Hour).After(t) {
+		start = start.Add(24 * time.Hour)
+	}
+	return NightFrom(obs, start)
+}
+
+// NightFrom рассчитывает сумерки на сутках, начинающихся в момент start
+func NightFrom(obs Observer, start time.Time) Night {
+	night := Night{Start: start, End: start.Add(24 * time.Hour)}
+	sunAltitude := func(t time.Time) float64 {
+		return SunHorizontal(obs, t).Altitude
+	}
+
+	night.Sunset, night.Sunrise = duskAndDawn(sunAltitude, night, SunriseAltitude-HorizonDip(obs.Elevation))
+	night.CivilDusk, night.CivilDawn = duskAndDawn(sunAltitude, night, CivilTwilightAltitude)
+	night.NauticalDusk, night.NauticalDawn = duskAndDawn(sunAltitude, night, NauticalTwilightAltitude)
+	night.AstronomicalDusk, night.AstronomicalDawn = duskAndDawn(sunAltitude, night, AstronomicalTwilightAltitude)
+	return night
+}
+
+// Dark возвращает промежуток наблюдений ночи: от конца вечерних до начала утренних
+// астрономических сумерек. Если Солнце не опускается так низко, берутся навигационные
+// сумерки, затем заход и восход Солнца, затем ночь целиком.
+func (n Night) Dark() Interval {
+	pairs := [][2]*time.Time{
+		{n.AstronomicalDusk, n.AstronomicalDawn},
+		{n.NauticalDusk, n.NauticalDawn},
+		{n.Sunset, n.Sunrise},
+	}
+	for _, p := range pairs {
+		if p[0] != nil && p[1] != nil && p[1].After(*p[0]) {
+			return Interval{Start: *p[0], End: *p[1]}
+		}
+	}
+	return Interval{Start: n.Start, End: n.End}
+}
+
+// duskAndDawn находит первое опускание ниже h0 и последний подъём выше h0 за ночь
+func duskAndDawn(altitude func(time.Time) float64, night Night, h0 float64) (dusk, dawn *time.Time) {
+	for _, c := range FindCrossings(altitude, night.Start, night.End, h0) {
+		c := c
+		if !c.Rising && dusk == nil {
+			dusk = &c.Time
+		}
+		if c.Rising {
+			dawn = &c.Time
+		}
+	}
+	return dusk, dawn
+}
+
+// In возвращает моменты ночи в часовом поясе loc
+func (n Night) In(loc *time.Location) Night {
+	in := func(t *time.Time) *time.Time {
+		if t == nil {
+			return nil
+		}
+		local := t.In(loc)
+		return &local
+	}
+	n.Start = n.Start.In(loc)
+	n.End = n.End.In(loc)
+	n.Sunset, n.CivilDusk, n.NauticalDusk, n.AstronomicalDusk = in(n.Sunset), in(n.CivilDusk), in(n.NauticalDusk), in(n.AstronomicalDusk)
+	n.AstronomicalDawn, n.NauticalDawn, n.CivilDawn, n.Sunrise = in(n.AstronomicalDawn), in(n.NauticalDawn), in(n.CivilDawn), in(n.Sunrise)
+	return n
+}
@@ -0,0 +1,45 @@
+package astro
+
+import "math"
+
+// Nutation возвращает нутацию в долготе и наклоне (градусы) для юлианской даты jd.
+// Используются главные члены ряда IAU 1980, точность около 0.5″ (Meeus, гл. 22).
+func Nutation(jd float64) (dPsi, dEps float64) {
+	T := JulianCenturies(jd)
+
+	omega := degToRad(125.04452 - 1934.136261*T + 0.0020708*T*T + T*T*T/450000)
+	L := degToRad(280.4665 + 36000.7698*T)   // средняя долгота Солнца
+	Lm := degToRad(218.3165 + 481267.8813*T) // средняя долгота Луны
+
+	dPsi = -17.20*math.Sin(omega) - 1.32*math.Sin(2*L) - 0.23*math.Sin(2*Lm) + 0.21*math.Sin(2*omega)
+	dEps = 9.20*math.Cos(omega) + 0.57*math.Cos(2*L) + 0.10*math.Cos(2*Lm) - 0.09*math.Cos(2*omega)
+
+	return dPsi / 3600, dEps / 3600
+}
+
+// MeanObliquity возвращает средний наклон эклиптики к экватору (градусы)
+func MeanObliquity(jd float64) float64 {
+	T := JulianCenturies(jd)
+	arcsec := 21.448 - 46.8150*T - 0.00059*T*T + 0.001813*T*T*T
+	return 23 + 26.0/60 + arcsec/3600
+}
+
+// TrueObliquity возвращает истинный наклон эклиптики с учётом нутации (градусы)
+func TrueObliquity(jd float64) float64 {
+	_, dEps := Nutation(jd)
+	return MeanObliquity(jd) + dEps
+}
+
+// nutate добавляет к средним координатам (градусы) нутацию
+func nutate(ra, dec, jd float64) (float64, float64) {
+	dPsi, dEps := Nutation(jd)
+	eps := degToRad(MeanObliquity(jd) + dEps)
+
+	a := degToRad(ra)
+	d := degToRad(dec)
+
+	dRA := (math.Cos(eps)+math.Sin(eps)*math.Sin(a)*math.Tan(d))*dPsi - math.Cos(a)*math.Tan(d)*dEps
+	dDec := math.Sin(eps)*math.Cos(a)*dPsi + math.Sin(a)*dEps
+
+	return normalizeDegrees(ra + dRA), dec + dDec
+}
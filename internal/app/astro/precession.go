@@ -0,0 +1,42 @@
+package astro
+
+import "math"
+
+// JulianEpochToJD переводит юлианскую эпоху (например, 2000.0) в юлианскую дату
+func JulianEpochToJD(epoch float64) float64 {
+	return J2000 + (epoch-2000.0)*365.25
+}
+
+// Precess переносит экваториальные координаты (ra, dec в градусах) со средних
+// экватора и равноденствия jd0 на средние экватор и равноденствие jd
+// (прецессия IAU 1976, Meeus, гл. 21)
+func Precess(ra, dec, jd0, jd float64) (float64, float64) {
+	T := JulianCenturies(jd0)
+	t := (jd - jd0) / 36525.0
+
+	// Углы прецессии в угловых секундах
+	base := 2306.2181 + 1.39656*T - 0.000139*T*T
+	zeta := base*t + (0.30188-0.000344*T)*t*t + 0.017998*t*t*t
+	z := base*t + (1.09468+0.000066*T)*t*t + 0.018203*t*t*t
+	theta := (2004.3109-0.85330*T-0.000217*T*T)*t - (0.42665+0.000217*T)*t*t - 0.041833*t*t*t
+
+	zetaR := arcsecToRad(zeta)
+	zR := arcsecToRad(z)
+	thetaR := arcsecToRad(theta)
+
+	a0 := degToRad(ra)
+	d0 := degToRad(dec)
+
+	A := math.Cos(d0) * math.Sin(a0+zetaR)
+	B := math.Cos(thetaR)*math.Cos(d0)*math.Cos(a0+zetaR) - math.Sin(thetaR)*math.Sin(d0)
+	C := math.Sin(thetaR)*math.Cos(d0)*math.Cos(a0+zetaR) + math.Cos(thetaR)*math.Sin(d0)
+
+	a := math.Atan2(A, B) + zR
+	d := math.Asin(clamp(C, -1, 1))
+
+	return normalizeDegrees(radToDeg(a)), radToDeg(d)
+}
+
+func arcsecToRad(arcsec float64) float64 {
+	return degToRad(arcsec / 3600)
+}
@@ -0,0 +1,18 @@
+package astro
+
+import "math"
+
+// AngularSeparation возвращает угловое расстояние по дуге большого круга
+// между двумя точками небесной сферы (все величины в градусах).
+// Формула Винсенти устойчива и для малых, и для близких к 180° расстояний.
+func AngularSeparation(ra1, dec1, ra2, dec2 float64) float64 {
+	d1 := degToRad(dec1)
+	d2 := degToRad(dec2)
+	dRA := degToRad(ra2 - ra1)
+
+	x := math.Cos(d2) * math.Sin(dRA)
+	y := math.Cos(d1)*math.Sin(d2) - math.Sin(d1)*math.Cos(d2)*math.Cos(dRA)
+	z := math.Sin(d1)*math.Sin(d2) + math.Cos(d1)*math.Cos(d2)*math.Cos(dRA)
+
+	return radToDeg(math.Atan2(math.Hypot(x, y), z))
+}
@@ -0,0 +1,106 @@
+package astro
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Прямое восхождение: "05h34m31.94s", "05 34 31.94", "05:34:31.94"
+var raPattern = regexp.MustCompile(`^(\d{1,2})(?:h\s*|:|\s+)(\d{1,2})(?:m\s*|:|\s+)(\d{1,2}(?:\.\d+)?)s?$`)
+
+// Склонение: "+22°00′52.2″", "+22d00m52.2s", "-05 23 28", "+22:00:52.2", "+22°00'52.2\""
+var decPattern = regexp.MustCompile(`^([+\-−]?)(\d{1,2})(?:°\s*|d\s*|:|\s+)(\d{1,2})(?:′\s*|'\s*|m\s*|:|\s+)(\d{1,2}(?:\.\d+)?)(?:″|"|s)?$`)
+
+// ParseRA разбирает прямое восхождение в шестидесятеричной записи (часы, минуты, секунды)
+// или десятичное число градусов и возвращает градусы
+func ParseRA(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if deg, err := strconv.ParseFloat(s, 64); err == nil {
+		if math.IsNaN(deg) || deg < 0 || deg >= 360 {
+			return 0, fmt.Errorf("прямое восхождение %q вне диапазона [0, 360)", s)
+		}
+		return deg, nil
+	}
+
+	m := raPattern.FindStringSubmatch(s)
+	if m == nil {
+		return 0, fmt.Errorf("некорректное прямое восхождение %q", s)
+	}
+
+	h, _ := strconv.Atoi(m[1])
+	min, _ := strconv.Atoi(m[2])
+	sec, _ := strconv.ParseFloat(m[3], 64)
+	if h > 23 || min > 59 || sec >= 60 {
+		return 0, fmt.Errorf("прямое восхождение %q вне диапазона", s)
+	}
+
+	return (float64(h) + float64(min)/60 + sec/3600) * 15, nil
+}
+
+// ParseDec разбирает склонение в шестидесятеричной записи (градусы, минуты, секунды)
+// или десятичное число градусов и возвращает градусы
+func ParseDec(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if deg, err := strconv.ParseFloat(s, 64); err == nil {
+		if math.IsNaN(deg) || deg < -90 || deg > 90 {
+			return 0, fmt.Errorf("склонение %q вне диапазона [-90, 90]", s)
+		}
+		return deg, nil
+	}
+
+	m := decPattern.FindStringSubmatch(s)
+	if m == nil {
+		return 0, fmt.Errorf("некорректное склонение %q", s)
+	}
+
+	d, _ := strconv.Atoi(m[2])
+	min, _ := strconv.Atoi(m[3])
+	sec, _ := strconv.ParseFloat(m[4], 64)
+	if min > 59 || sec >= 60 {
+		return 0, fmt.Errorf("склонение %q вне диапазона", s)
+	}
+
+	deg := float64(d) + float64(min)/60 + sec/3600
+	if deg > 90 {
+		return 0, fmt.Errorf("склонение %q вне диапазона [-90, 90]", s)
+	}
+	if m[1] == "-" || m[1] == "−" {
+		deg = -deg
+	}
+	return deg, nil
+}
+
+// FormatRA записывает прямое восхождение (градусы) как "05h34m31.94s"
+func FormatRA(deg float64) string {
+	// Округляем до сотых секунды времени заранее, чтобы не получить "60.00s"
+	total := math.Round(normalizeDegrees(deg)/15*3600*100) / 100
+	if total >= 24*3600 {
+		total -= 24 * 3600
+	}
+
+	h := int(total / 3600)
+	m := int((total - float64(h)*3600) / 60)
+	s := total - float64(h)*3600 - float64(m)*60
+
+	return fmt.Sprintf("%02dh%02dm%05.2fs", h, m, s)
+}
+
+// FormatDec записывает склонение (градусы) как "+22°00′52.2″"
+func FormatDec(deg float64) string {
+	// Округляем до десятых угловой секунды
+	total := math.Round(math.Abs(deg)*3600*10) / 10
+
+	sign := "+"
+	if deg < 0 && total > 0 {
+		sign = "-"
+	}
+
+	d := int(total / 3600)
+	m := int((total - float64(d)*3600) / 60)
+	s := total - float64(d)*3600 - float64(m)*60
+
+	return fmt.Sprintf("%s%02d°%02d′%04.1f″", sign, d, m, s)
+}
@@ -0,0 +1,48 @@
+package astro
+
+import (
+	"math"
+	"time"
+)
+
+// Sky — положение Солнца и Луны для наблюдателя в заданный момент
+type Sky struct {
+	SunAltitude float64 // градусы
+	SunAzimuth  float64
+	Twilight    string // фаза суток: day, civil, nautical, astronomical, night
+	Night       Night
+
+	MoonRA       float64 // топоцентрические координаты Луны, градусы
+	MoonDec      float64
+	MoonAltitude float64
+	MoonAzimuth  float64
+	MoonDistance float64 // расстояние до центра Луны, км
+	MoonPhase    MoonPhase
+}
+
+// SkyAt рассчитывает положение Солнца и Луны, сумерки и фазу Луны для наблюдателя obs в момент t
+func SkyAt(obs Observer, t time.Time) Sky {
+	sun := SunHorizontal(obs, t)
+
+	moonRA, moonDec, moonDistance := MoonPosition(t)
+	moonRA, moonDec = Topocentric(moonRA, moonDec, moonDistance, obs, t)
+	moon := EquatorialToHorizontal(moonRA, moonDec, obs, t)
+
+	return Sky{
+		SunAltitude:  sun.Altitude,
+		SunAzimuth:   sun.Azimuth,
+		Twilight:     TwilightPhase(sun.Altitude),
+		Night:        NightAt(obs, t),
+		MoonRA:       moonRA,
+		MoonDec:      moonDec,
+		MoonAltitude: moon.Altitude,
+		MoonAzimuth:  moon.Azimuth,
+		MoonDistance: math.Round(moonDistance),
+		MoonPhase:    MoonPhaseAt(t),
+	}
+}
+
+// MoonSeparation возвращает угловое расстояние (градусы) от Луны до точки (ra, dec)
+func (s Sky) MoonSeparation(ra, dec float64) float64 {
+	return AngularSeparation(s.MoonRA, s.MoonDec, ra, dec)
+}
@@ -0,0 +1,68 @@
+package astro
+
+import (
+	"math"
+	"time"
+)
+
+// Высоты центра Солнца, задающие восход/заход и границы сумерек, градусы
+const (
+	SunriseAltitude              = -0.8333
+	CivilTwilightAltitude        = -6
+	NauticalTwilightAltitude     = -12
+	AstronomicalTwilightAltitude = -18
+)
+
+// Фазы суток по высоте Солнца
+const (
+	TwilightDay          = "day"
+	TwilightCivil        = "civil"
+	TwilightNautical     = "nautical"
+	TwilightAstronomical = "astronomical"
+	TwilightNight        = "night"
+)
+
+// SunPosition возвращает видимые геоцентрические координаты Солнца (градусы)
+// и расстояние до него в астрономических единицах (Meeus, гл. 25)
+func SunPosition(t time.Time) (ra, dec, distance float64) {
+	jd := JulianDate(t)
+	T := JulianCenturies(jd)
+
+	M := degToRad(357.52911 + 35999.05029*T - 0.0001537*T*T)
+	e := 0.016708634 - 0.000042037*T - 0.0000001267*T*T
+	C := (1.914602-0.004817*T-0.000014*T*T)*math.Sin(M) +
+		(0.019993-0.000101*T)*math.Sin(2*M) +
+		0.000289*math.Sin(3*M)
+	nu := M + degToRad(C)
+	distance = 1.000001018 * (1 - e*e) / (1 + e*math.Cos(nu))
+
+	// Видимая долгота: поправки за нутацию и аберрацию
+	omega := degToRad(125.04 - 1934.136*T)
+	lambda := SunTrueLongitude(jd) - 0.00569 - 0.00478*math.Sin(omega)
+	eps := TrueObliquity(jd)
+
+	ra, dec = EclipticToEquatorial(Ecliptic{Lon: lambda}, eps)
+	return ra, dec, distance
+}
+
+// SunHorizontal возвращает положение Солнца на небе для наблюдателя obs в момент t
+func SunHorizontal(obs Observer, t time.Time) Horizontal {
+	ra, dec, _ := SunPosition(t)
+	return EquatorialToHorizontal(ra, dec, obs, t)
+}
+
+// TwilightPhase определяет фазу суток по высоте Солнца (градусы)
+func TwilightPhase(sunAltitude float64) string {
+	switch {
+	case sunAltitude >= SunriseAltitude:
+		return TwilightDay
+	case sunAltitude >= CivilTwilightAltitude:
+		return TwilightCivil
+	case sunAltitude >= NauticalTwilightAltitude:
+		return TwilightNautical
+	case sunAltitude >= AstronomicalTwilightAltitude:
+		return TwilightAstronomical
+	default:
+		return TwilightNight
+	}
+}
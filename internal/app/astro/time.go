@@ -0,0 +1,67 @@
+package astro
+
+import (
+	"math"
+	"time"
+)
+
+// Юлианская дата эпохи J2000.0 (2000-01-01 12:00 TT)
+const J2000 = 2451545.0
+
+// JulianDate возвращает юлианскую дату для момента времени t (шкала UTC)
+func JulianDate(t time.Time) float64 {
+	t = t.UTC()
+	// 2440587.5 — юлианская дата начала эпохи Unix
+	return 2440587.5 + float64(t.UnixNano())/86400e9
+}
+
+// JulianCenturies — число юлианских столетий, прошедших от J2000.0
+func JulianCenturies(jd float64) float64 {
+	return (jd - J2000) / 36525.0
+}
+
+// GreenwichMeanSiderealTime возвращает среднее звёздное время в Гринвиче в часах [0, 24)
+func GreenwichMeanSiderealTime(t time.Time) float64 {
+	jd := JulianDate(t)
+	T := JulianCenturies(jd)
+
+	// IAU 1982, формула Meeus (12.4), результат в градусах
+	gmst := 280.46061837 +
+		360.98564736629*(jd-J2000) +
+		0.000387933*T*T -
+		T*T*T/38710000.0
+
+	return normalizeDegrees(gmst) / 15.0
+}
+
+// GreenwichApparentSiderealTime возвращает истинное звёздное время в Гринвиче
+// в часах [0, 24): среднее время, исправленное на уравнение равноденствий
+func GreenwichApparentSiderealTime(t time.Time) float64 {
+	jd := JulianDate(t)
+	dPsi, _ := Nutation(jd)
+	eqEquinoxes := dPsi * math.Cos(degToRad(TrueObliquity(jd)))
+
+	return normalizeHours(GreenwichMeanSiderealTime(t) + eqEquinoxes/15.0)
+}
+
+// LocalSiderealTime возвращает местное истинное звёздное время в часах [0, 24).
+// longitude — восточная долгота в градусах (западная — отрицательная).
+func LocalSiderealTime(t time.Time, longitude float64) float64 {
+	return normalizeHours(GreenwichApparentSiderealTime(t) + longitude/15.0)
+}
+
+func normalizeDegrees(deg float64) float64 {
+	deg = math.Mod(deg, 360)
+	if deg < 0 {
+		deg += 360
+	}
+	return deg
+}
+
+func normalizeHours(h float64) float64 {
+	h = math.Mod(h, 24)
+	if h < 0 {
+		h += 24
+	}
+	return h
+}
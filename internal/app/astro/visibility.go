@@ -0,0 +1,94 @@
+package astro
+
+import (
+	"math"
+	"time"
+)
+
+// Стандартная высота восхода/захода звезды с учётом рефракции, градусы
+const StandardAltitude = -0.5667
+
+// Отношение среднего солнечного времени к звёздному
+const siderealToSolar = 0.9972695663
+
+// Visibility — восход, верхняя кульминация и заход звезды
+type Visibility struct {
+	Rise        *time.Time // nil, если звезда не восходит или не заходит
+	Transit     time.Time  // верхняя кульминация, ближайшая к моменту наблюдения
+	Set         *time.Time
+	Circumpolar bool // звезда никогда не заходит
+	NeverRises  bool // звезда никогда не восходит
+}
+
+// RiseTransitSet рассчитывает восход, кульминацию и заход звезды (ra, dec в градусах)
+// для наблюдателя obs. Берётся кульминация, ближайшая к моменту t.
+// Восход и заход отсчитываются от высоты obs.HorizonAltitude().
+func RiseTransitSet(ra, dec float64, obs Observer, t time.Time) Visibility {
+	hor := EquatorialToHorizontal(ra, dec, obs, t)
+	transit := t.Add(-solarDuration(hor.HourAngle))
+
+	v := Visibility{Transit: transit}
+
+	phi := degToRad(obs.Latitude)
+	d := degToRad(dec)
+	cosH0 := (math.Sin(degToRad(obs.HorizonAltitude())) - math.Sin(phi)*math.Sin(d)) /
+		(math.Cos(phi) * math.Cos(d))
+
+	switch {
+	case cosH0 < -1:
+		v.Circumpolar = true
+	case cosH0 > 1:
+		v.NeverRises = true
+	default:
+		// Полудуговой часовой угол в звёздных часах
+		h0 := radToDeg(math.Acos(cosH0)) / 15.0
+		rise := transit.Add(-solarDuration(h0))
+		set := transit.Add(solarDuration(h0))
+		v.Rise = &rise
+		v.Set = &set
+	}
+
+	return v
+}
+
+// PeakAltitude возвращает наибольшую высоту звезды (ra, dec в градусах) на промежутке [from, to]
+// и момент, когда она достигается: верхнюю кульминацию, если она попадает в промежуток,
+// иначе один из его концов
+func PeakAltitude(ra, dec float64, obs Observer, from, to time.Time) (altitude float64, at time.Time) {
+	start := EquatorialToHorizontal(ra, dec, obs, from)
+	end := EquatorialToHorizontal(ra, dec, obs, to)
+
+	// До ближайшей кульминации после from, звёздные часы
+	untilTransit := -start.HourAngle
+	if untilTransit < 0 {
+		untilTransit += 24
+	}
+	transit := from.Add(solarDuration(untilTransit))
+	if !transit.After(to) {
+		return EquatorialToHorizontal(ra, dec, obs, transit).Altitude, transit
+	}
+
+	if start.Altitude >= end.Altitude {
+		return start.Altitude, from
+	}
+	return end.Altitude, to
+}
+
+// In возвращает те же моменты восхода, кульминации и захода в часовом поясе loc
+func (v Visibility) In(loc *time.Location) Visibility {
+	v.Transit = v.Transit.In(loc)
+	if v.Rise != nil {
+		rise := v.Rise.In(loc)
+		v.Rise = &rise
+	}
+	if v.Set != nil {
+		set := v.Set.In(loc)
+		v.Set = &set
+	}
+	return v
+}
+
+// solarDuration переводит интервал в звёздных часах в промежуток солнечного времени
+func solarDuration(siderealHours float64) time.Duration {
+	return time.Duration(siderealHours * siderealToSolar * float64(time.Hour))
+}
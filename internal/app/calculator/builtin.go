@@ -0,0 +1,116 @@
+package calculator
+
+import (
+	"Lab1/internal/app/astro"
+	"Lab1/internal/app/models"
+	"math"
+)
+
+func init() {
+	Register(altitudeCalculator{})
+	Register(airmassCalculator{})
+	Register(snrCalculator{})
+	Register(limitingMagnitudeCalculator{})
+	Register(legacyCalculator{})
+}
+
+// horizontal рассчитывает положение звезды на небе для места и даты наблюдения из заявки
+func horizontal(order *models.TelescopeObservation, star models.Star) astro.Horizontal {
+	observedAt := order.ObservedAt()
+	ra, dec := star.ApparentPosition(observedAt)
+	return astro.EquatorialToHorizontal(ra, dec, order.Observer(), observedAt)
+}
+
+// withAtmosphere дополняет результат воздушной массой и экстинкцией
+// для места наблюдения, если звезда над горизонтом
+func withAtmosphere(res Result, altitude float64, observer astro.Observer) Result {
+	if x, ok := astro.Airmass(altitude); ok {
+		res.Airmass = round(x, 3)
+		res.Extinction = round(astro.Extinction(*res.Airmass, observer.ExtinctionCoefficient()), 3)
+	}
+	return res
+}
+
+// altitudeCalculator — высота звезды над горизонтом, градусы
+type altitudeCalculator struct{}
+
+func (altitudeCalculator) Name() string    { return "altitude" }
+func (altitudeCalculator) Version() string { return "1" }
+func (altitudeCalculator) Unit() string    { return "deg" }
+func (altitudeCalculator) UCD() string     { return "pos.az.alt" }
+
+func (altitudeCalculator) Calculate(order *models.TelescopeObservation, star models.Star) Result {
+	hor := horizontal(order, star)
+	return withAtmosphere(Result{Value: round(hor.Altitude, 2)}, hor.Altitude, order.Observer())
+}
+
+// airmassCalculator — воздушная масса; для звезды под горизонтом не определена
+type airmassCalculator struct{}
+
+func (airmassCalculator) Name() string    { return "airmass" }
+func (airmassCalculator) Version() string { return "1" }
+func (airmassCalculator) Unit() string    { return "" }
+func (airmassCalculator) UCD() string     { return "obs.airMass" }
+
+func (airmassCalculator) Calculate(order *models.TelescopeObservation, star models.Star) Result {
+	hor := horizontal(order, star)
+	res := withAtmosphere(Result{}, hor.Altitude, order.Observer())
+	res.Value = res.Airmass
+	return res
+}
+
+// snrCalculator — отношение сигнал/шум относительно наблюдения в зените.
+// Для шумов фотонной статистики SNR ∝ √потока, поток ослабляется на k·(X−1) звёздных величин.
+// С версии 2 коэффициент k учитывает высоту места наблюдения.
+type snrCalculator struct{}
+
+func (snrCalculator) Name() string    { return "snr" }
+func (snrCalculator) Version() string { return "2" }
+func (snrCalculator) Unit() string    { return "" }
+func (snrCalculator) UCD() string     { return "stat.snr" }
+
+func (snrCalculator) Calculate(order *models.TelescopeObservation, star models.Star) Result {
+	hor := horizontal(order, star)
+	res := withAtmosphere(Result{}, hor.Altitude, order.Observer())
+	if res.Airmass != nil {
+		k := order.Observer().ExtinctionCoefficient()
+		relative := math.Pow(10, -0.2*k*(*res.Airmass-1))
+		res.Value = round(relative, 3)
+	}
+	return res
+}
+
+// limitingMagnitudeCalculator — предельная звёздная величина телескопа заявки
+// в направлении на звезду: предел в зените ослабляется на k·(X−1) звёздных величин.
+// Без телескопа результат не рассчитывается.
+// В версии 1 вычиталась полная экстинкция k·X, хотя предел в зените её уже учитывает.
+type limitingMagnitudeCalculator struct{}
+
+func (limitingMagnitudeCalculator) Name() string    { return "limiting_magnitude" }
+func (limitingMagnitudeCalculator) Version() string { return "2" }
+func (limitingMagnitudeCalculator) Unit() string    { return "mag" }
+func (limitingMagnitudeCalculator) UCD() string     { return "phot.mag;stat.max" }
+
+func (limitingMagnitudeCalculator) Calculate(order *models.TelescopeObservation, star models.Star) Result {
+	hor := horizontal(order, star)
+	res := withAtmosphere(Result{}, hor.Altitude, order.Observer())
+	if res.Airmass != nil && order.Telescope != nil {
+		k := order.Observer().ExtinctionCoefficient()
+		res.Value = round(order.Telescope.MagnitudeLimit()-astro.Extinction(*res.Airmass-1, k), 2)
+	}
+	return res
+}
+
+// legacyCalculator — исходная формула √(RA² + Dec²), которой завершались заявки через API.
+// Оставлена для воспроизведения старых результатов.
+type legacyCalculator struct{}
+
+func (legacyCalculator) Name() string    { return "legacy" }
+func (legacyCalculator) Version() string { return "1" }
+func (legacyCalculator) Unit() string    { return "" }
+func (legacyCalculator) UCD() string     { return "meta.number" }
+
+func (legacyCalculator) Calculate(order *models.TelescopeObservation, star models.Star) Result {
+	value := math.Sqrt(math.Pow(star.RA, 2) + math.Pow(star.Dec, 2))
+	return Result{Value: round(value, 2)}
+}
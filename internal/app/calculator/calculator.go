@@ -0,0 +1,92 @@
+package calculator
+
+import (
+	"Lab1/internal/app/models"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Калькулятор, которым завершаются заявки без явного выбора
+const Default = "altitude"
+
+// Result — результат расчёта для одной звезды заявки
+type Result struct {
+	StarID     int
+	Value      *float64 // nil, если величина не определена (например, звезда под горизонтом)
+	Airmass    *float64
+	Extinction *float64
+}
+
+// Calculator — способ расчёта result_value для звезды заявки
+type Calculator interface {
+	Name() string
+	Version() string
+	Unit() string // единица result_value (VOUnits)
+	UCD() string  // смысл result_value по словарю UCD IVOA
+	Calculate(order *models.TelescopeObservation, star models.Star) Result
+}
+
+var registry = map[string]Calculator{}
+
+// Register добавляет калькулятор в реестр; повторная регистрация имени — ошибка программы
+func Register(c Calculator) {
+	if _, ok := registry[c.Name()]; ok {
+		panic("calculator: повторная регистрация " + c.Name())
+	}
+	registry[c.Name()] = c
+}
+
+// Get возвращает калькулятор по имени
+func Get(name string) (Calculator, error) {
+	c, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("неизвестный калькулятор %q", name)
+	}
+	return c, nil
+}
+
+// All возвращает все зарегистрированные калькуляторы, отсортированные по имени
+func All() []Calculator {
+	list := make([]Calculator, 0, len(registry))
+	for _, c := range registry {
+		list = append(list, c)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name() < list[j].Name() })
+	return list
+}
+
+// ForOrder возвращает калькулятор, выбранный в заявке, или калькулятор по умолчанию
+func ForOrder(order *models.TelescopeObservation) (Calculator, error) {
+	if order.Calculator == "" {
+		return Get(Default)
+	}
+	return Get(order.Calculator)
+}
+
+// Complete рассчитывает результаты для звёзд заявки и записывает
+// в заявку название и версию использованного калькулятора
+func Complete(order *models.TelescopeObservation, stars []models.TelescopeObservationStar) ([]Result, error) {
+	calc, err := ForOrder(order)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, 0, len(stars))
+	for _, s := range stars {
+		res := calc.Calculate(order, s.Star)
+		res.StarID = s.StarID
+		results = append(results, res)
+	}
+
+	order.Calculator = calc.Name()
+	order.CalculatorVersion = calc.Version()
+
+	return results, nil
+}
+
+func round(x float64, digits int) *float64 {
+	p := math.Pow(10, float64(digits))
+	v := math.Round(x*p) / p
+	return &v
+}
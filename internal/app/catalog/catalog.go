@@ -0,0 +1,43 @@
+package catalog
+
+import (
+	"Lab1/internal/app/models"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Поддерживаемые форматы каталогов
+const (
+	FormatCSV       = "csv"
+	FormatBSC       = "bsc"       // Yale Bright Star Catalog, 5-я редакция (bsc5.dat)
+	FormatHipparcos = "hipparcos" // основной каталог Hipparcos (hip_main.dat)
+)
+
+// Row — разобранная строка каталога
+type Row struct {
+	Line   int
+	Star   models.Star
+	Fields []string // столбцы БД, заполненные этой строкой
+}
+
+// RowError — ошибка разбора или сохранения строки каталога
+type RowError struct {
+	Line        int    `json:"line"`
+	Designation string `json:"designation,omitempty"`
+	Error       string `json:"error"`
+}
+
+// Parse читает каталог в формате format
+func Parse(format string, r io.Reader) ([]Row, []RowError, error) {
+	switch strings.ToLower(format) {
+	case FormatCSV:
+		return parseCSV(r)
+	case FormatBSC:
+		return parseFixedWidth(r, parseBSCLine)
+	case FormatHipparcos:
+		return parseFixedWidth(r, parseHipparcosLine)
+	default:
+		return nil, nil, fmt.Errorf("неизвестный формат каталога %q (допустимы %s, %s, %s)", format, FormatCSV, FormatBSC, FormatHipparcos)
+	}
+}
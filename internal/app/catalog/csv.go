@@ -0,0 +1,193 @@
+package catalog
+
+import (
+	"Lab1/internal/app/astro"
+	"Lab1/internal/app/models"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Допустимые заголовки CSV и соответствующие им столбцы БД
+var csvColumns = map[string]string{
+	"designation":       "designation",
+	"name":              "star_name",
+	"star_name":         "star_name",
+	"short_description": "short_description",
+	"description":       "description",
+	"image_url":         "image_url",
+	"ra":                "ra",
+	"dec":               "dec",
+	"epoch":             "epoch",
+	"frame":             "frame",
+	"pm_ra":             "pm_ra",
+	"pmra":              "pm_ra",
+	"pm_dec":            "pm_dec",
+	"pmdec":             "pm_dec",
+	"parallax":          "parallax",
+	"plx":               "parallax",
+	"radial_velocity":   "radial_velocity",
+	"rv":                "radial_velocity",
+	"v_mag":             "v_mag",
+	"vmag":              "v_mag",
+	"mag":               "v_mag",
+	"b_v":               "b_v",
+	"bv":                "b_v",
+	"b-v":               "b_v",
+	"spectral_type":     "spectral_type",
+	"sptype":            "spectral_type",
+	"sp_type":           "spectral_type",
+}
+
+// parseCSV читает CSV с заголовком. Обязательны столбцы designation, ra и dec;
+// координаты принимаются в градусах или в шестидесятеричной записи.
+func parseCSV(r io.Reader) ([]Row, []RowError, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("не удалось прочитать заголовок CSV: %w", err)
+	}
+
+	columns := make([]string, len(header))
+	seen := map[string]bool{}
+	for i, name := range header {
+		col := csvColumns[strings.ToLower(strings.TrimSpace(name))]
+		if col != "" && seen[col] {
+			return nil, nil, fmt.Errorf("столбец %q указан в заголовке дважды", name)
+		}
+		columns[i] = col // неизвестные столбцы пропускаются
+		seen[col] = true
+	}
+	for _, required := range []string{"designation", "ra", "dec"} {
+		if !seen[required] {
+			return nil, nil, fmt.Errorf("в заголовке CSV нет обязательного столбца %q", required)
+		}
+	}
+
+	var rows []Row
+	var rowErrors []RowError
+	line := 1
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			rowErrors = append(rowErrors, RowError{Line: line, Error: err.Error()})
+			continue
+		}
+
+		row, err := parseCSVRecord(columns, record)
+		row.Line = line
+		if err != nil {
+			rowErrors = append(rowErrors, RowError{Line: line, Designation: row.Star.Designation, Error: err.Error()})
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, rowErrors, nil
+}
+
+func parseCSVRecord(columns, record []string) (Row, error) {
+	row := Row{Star: models.Star{IsActive: true}}
+
+	for i, col := range columns {
+		if col == "" || i >= len(record) {
+			continue
+		}
+		value := strings.TrimSpace(record[i])
+		if value == "" {
+			continue
+		}
+
+		var err error
+		s := &row.Star
+		switch col {
+		case "designation":
+			s.Designation = value
+		case "star_name":
+			s.StarName = value
+		case "short_description":
+			s.ShortDescription = value
+		case "description":
+			s.Description = value
+		case "image_url":
+			s.ImageURL = value
+		case "ra":
+			s.RA, err = astro.ParseRA(value)
+		case "dec":
+			s.Dec, err = astro.ParseDec(value)
+		case "epoch":
+			s.Epoch, err = parseNumber(col, value)
+		case "frame":
+			s.Frame = strings.ToUpper(value)
+			if !astro.ValidFrame(s.Frame) {
+				err = fmt.Errorf("неподдерживаемая система отсчёта %q", value)
+			}
+		case "pm_ra":
+			s.PMRA, err = parseNumber(col, value)
+		case "pm_dec":
+			s.PMDec, err = parseNumber(col, value)
+		case "parallax":
+			s.Parallax, err = parseNumber(col, value)
+		case "radial_velocity":
+			s.RadialVelocity, err = parseNumber(col, value)
+		case "v_mag":
+			s.VMag, err = parseOptionalNumber(col, value)
+		case "b_v":
+			s.BV, err = parseOptionalNumber(col, value)
+		case "spectral_type":
+			s.SpectralType = value
+		}
+		if err != nil {
+			return row, err
+		}
+		row.Fields = append(row.Fields, col)
+	}
+
+	if row.Star.Designation == "" {
+		return row, fmt.Errorf("не указано обозначение звезды")
+	}
+	if !contains(row.Fields, "ra") || !contains(row.Fields, "dec") {
+		return row, fmt.Errorf("не указаны координаты звезды")
+	}
+	if row.Star.StarName == "" {
+		row.Star.StarName = row.Star.Designation
+		row.Fields = append(row.Fields, "star_name")
+	}
+	return row, nil
+}
+
+func parseNumber(col, value string) (float64, error) {
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("столбец %s: %q не число", col, value)
+	}
+	return v, nil
+}
+
+// parseOptionalNumber разбирает величину, которая может отсутствовать в каталоге
+func parseOptionalNumber(col, value string) (*float64, error) {
+	v, err := parseNumber(col, value)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,251 @@
+package catalog
+
+import (
+	"Lab1/internal/app/astro"
+	"Lab1/internal/app/models"
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Момент J2000.0, на который переносятся положения каталогов других эпох
+var j2000 = time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// Эпоха положений каталога Hipparcos
+const hipparcosEpoch = 1991.25
+
+// parseFixedWidth построчно разбирает каталог в формате фиксированной ширины.
+// parseLine возвращает ok = false для строк, которые нужно пропустить.
+func parseFixedWidth(r io.Reader, parseLine func(string) (Row, bool, error)) ([]Row, []RowError, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var rows []Row
+	var rowErrors []RowError
+	line := 0
+
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		row, ok, err := parseLine(text)
+		if err != nil {
+			rowErrors = append(rowErrors, RowError{Line: line, Designation: row.Star.Designation, Error: err.Error()})
+			continue
+		}
+		if !ok {
+			continue
+		}
+		row.Line = line
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return rows, rowErrors, nil
+}
+
+// column возвращает обрезанное содержимое байтов [from, to] строки (нумерация с 1, как в ReadMe каталогов)
+func column(line string, from, to int) string {
+	if from > len(line) {
+		return ""
+	}
+	if to > len(line) {
+		to = len(line)
+	}
+	return strings.TrimSpace(line[from-1 : to])
+}
+
+func optionalNumber(name, value string) (float64, bool, error) {
+	if value == "" {
+		return 0, false, nil
+	}
+	v, err := strconv.ParseFloat(strings.TrimPrefix(value, "+"), 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("поле %s: %q не число", name, value)
+	}
+	return v, true, nil
+}
+
+// parseBSCLine разбирает строку bsc5.dat (координаты J2000, FK5)
+func parseBSCLine(line string) (Row, bool, error) {
+	hr := column(line, 1, 4)
+	if hr == "" {
+		return Row{}, false, fmt.Errorf("нет номера HR")
+	}
+	row := Row{Star: models.Star{Designation: "HR " + hr, IsActive: true}}
+	s := &row.Star
+
+	s.StarName = strings.Join(strings.Fields(column(line, 5, 14)), " ")
+	if s.StarName == "" {
+		s.StarName = s.Designation
+	}
+
+	raH, raM, raS := column(line, 76, 77), column(line, 78, 79), column(line, 80, 83)
+	decSign, decD, decM, decS := column(line, 84, 84), column(line, 85, 86), column(line, 87, 88), column(line, 89, 90)
+	if raH == "" || decD == "" {
+		// В BSC есть записи без координат (новые, внегалактические объекты)
+		return row, false, fmt.Errorf("нет координат J2000")
+	}
+
+	var err error
+	if s.RA, err = astro.ParseRA(fmt.Sprintf("%s %s %s", raH, raM, raS)); err != nil {
+		return row, false, err
+	}
+	if s.Dec, err = astro.ParseDec(fmt.Sprintf("%s%s %s %s", decSign, decD, decM, decS)); err != nil {
+		return row, false, err
+	}
+	s.Epoch = astro.DefaultEpoch
+	s.Frame = astro.FrameFK5
+	row.Fields = []string{"designation", "star_name", "ra", "dec", "epoch", "frame"}
+
+	// Собственные движения и параллакс в BSC даны в угловых секундах
+	if v, ok, err := optionalNumber("pmRA", column(line, 149, 154)); err != nil {
+		return row, false, err
+	} else if ok {
+		s.PMRA = v * 1000
+		row.Fields = append(row.Fields, "pm_ra")
+	}
+	if v, ok, err := optionalNumber("pmDE", column(line, 155, 160)); err != nil {
+		return row, false, err
+	} else if ok {
+		s.PMDec = v * 1000
+		row.Fields = append(row.Fields, "pm_dec")
+	}
+	// n_Parallax = D — динамический параллакс, его не используем
+	if column(line, 161, 161) != "D" {
+		if v, ok, err := optionalNumber("Parallax", column(line, 162, 166)); err != nil {
+			return row, false, err
+		} else if ok && v > 0 {
+			s.Parallax = v * 1000
+			row.Fields = append(row.Fields, "parallax")
+		}
+	}
+	if v, ok, err := optionalNumber("RadVel", column(line, 167, 170)); err != nil {
+		return row, false, err
+	} else if ok {
+		s.RadialVelocity = v
+		row.Fields = append(row.Fields, "radial_velocity")
+	}
+	if err := parsePhotometry(s, column(line, 103, 107), column(line, 110, 114), column(line, 128, 147)); err != nil {
+		return row, false, err
+	}
+	row.Fields = photometryFields(row.Fields, s)
+
+	return row, true, nil
+}
+
+// parseHipparcosLine разбирает строку hip_main.dat (поля разделены "|").
+// Положения Hipparcos даны на эпоху J1991.25 и переносятся на J2000.0.
+func parseHipparcosLine(line string) (Row, bool, error) {
+	f := strings.Split(line, "|")
+	if len(f) < 14 {
+		return Row{}, false, fmt.Errorf("ожидалось не меньше 14 полей, получено %d", len(f))
+	}
+	for i := range f {
+		f[i] = strings.TrimSpace(f[i])
+	}
+
+	if f[1] == "" {
+		return Row{}, false, fmt.Errorf("нет номера HIP")
+	}
+	row := Row{Star: models.Star{Designation: "HIP " + f[1], StarName: "HIP " + f[1], IsActive: true}}
+	s := &row.Star
+
+	var err error
+	if f[8] != "" && f[9] != "" {
+		if s.RA, err = astro.ParseRA(f[8]); err != nil {
+			return row, false, err
+		}
+		if s.Dec, err = astro.ParseDec(f[9]); err != nil {
+			return row, false, err
+		}
+	} else if f[3] != "" && f[4] != "" {
+		if s.RA, err = astro.ParseRA(f[3]); err != nil {
+			return row, false, err
+		}
+		if s.Dec, err = astro.ParseDec(f[4]); err != nil {
+			return row, false, err
+		}
+	} else {
+		return row, false, fmt.Errorf("нет координат")
+	}
+
+	row.Fields = []string{"designation", "star_name", "ra", "dec", "epoch", "frame"}
+
+	if v, ok, err := optionalNumber("Plx", f[11]); err != nil {
+		return row, false, err
+	} else if ok && v > 0 {
+		s.Parallax = v
+		row.Fields = append(row.Fields, "parallax")
+	}
+	if v, ok, err := optionalNumber("pmRA", f[12]); err != nil {
+		return row, false, err
+	} else if ok {
+		s.PMRA = v
+		row.Fields = append(row.Fields, "pm_ra")
+	}
+	if v, ok, err := optionalNumber("pmDE", f[13]); err != nil {
+		return row, false, err
+	} else if ok {
+		s.PMDec = v
+		row.Fields = append(row.Fields, "pm_dec")
+	}
+
+	// Vmag (H5), B−V (H37) и спектральный класс (H76)
+	if err := parsePhotometry(s, f[5], field(f, 37), field(f, 76)); err != nil {
+		return row, false, err
+	}
+	row.Fields = photometryFields(row.Fields, s)
+
+	s.RA, s.Dec = astro.Propagate(s.RA, s.Dec, s.Motion(), hipparcosEpoch, j2000)
+	s.Epoch = astro.DefaultEpoch
+	s.Frame = astro.FrameICRS
+
+	return row, true, nil
+}
+
+// field возвращает i-е поле строки или пустую строку, если полей меньше
+func field(f []string, i int) string {
+	if i >= len(f) {
+		return ""
+	}
+	return f[i]
+}
+
+// parsePhotometry заполняет звёздную величину V, показатель цвета B−V и спектральный класс
+func parsePhotometry(s *models.Star, vmag, bv, spType string) error {
+	if v, ok, err := optionalNumber("Vmag", vmag); err != nil {
+		return err
+	} else if ok {
+		s.VMag = &v
+	}
+	if v, ok, err := optionalNumber("B-V", bv); err != nil {
+		return err
+	} else if ok {
+		s.BV = &v
+	}
+	s.SpectralType = spType
+	return nil
+}
+
+// photometryFields добавляет к столбцам строки заполненные фотометрические величины
+func photometryFields(fields []string, s *models.Star) []string {
+	if s.VMag != nil {
+		fields = append(fields, "v_mag")
+	}
+	if s.BV != nil {
+		fields = append(fields, "b_v")
+	}
+	if s.SpectralType != "" {
+		fields = append(fields, "spectral_type")
+	}
+	return fields
+}
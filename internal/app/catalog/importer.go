@@ -0,0 +1,174 @@
+package catalog
+
+import (
+	"Lab1/internal/app/models"
+	"Lab1/internal/app/repository"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Действия импорта над строкой каталога
+const (
+	ActionCreate    = "create"
+	ActionUpdate    = "update"
+	ActionUnchanged = "unchanged"
+)
+
+// FieldChange — изменение одного столбца звезды
+type FieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// Change — результат импорта одной строки каталога
+type Change struct {
+	Line        int                    `json:"line"`
+	Designation string                 `json:"designation"`
+	Action      string                 `json:"action"`
+	StarID      int                    `json:"star_id,omitempty"`
+	Fields      map[string]FieldChange `json:"fields,omitempty"`
+}
+
+// Report — отчёт об импорте каталога
+type Report struct {
+	DryRun    bool       `json:"dry_run"`
+	Created   int        `json:"created"`
+	Updated   int        `json:"updated"`
+	Unchanged int        `json:"unchanged"`
+	Changes   []Change   `json:"changes"`
+	Errors    []RowError `json:"errors"`
+}
+
+// Import сопоставляет строки каталога со звёздами по обозначению и создаёт
+// или обновляет их. При dryRun изменения только рассчитываются и в БД не пишутся.
+// Каждая строка пишется в своей точке сохранения: ошибка строки попадает в отчёт
+// и откатывает только её, остальные строки импортируются.
+func Import(repo *repository.Repository, rows []Row, parseErrors []RowError, dryRun bool) (*Report, error) {
+	report := &Report{
+		DryRun:  dryRun,
+		Changes: []Change{},
+		Errors:  append([]RowError{}, parseErrors...),
+	}
+
+	err := repo.DB.Transaction(func(tx *gorm.DB) error {
+		seen := map[string]int{}
+
+		for _, row := range rows {
+			if first, ok := seen[row.Star.Designation]; ok {
+				report.Errors = append(report.Errors, RowError{
+					Line:        row.Line,
+					Designation: row.Star.Designation,
+					Error:       fmt.Sprintf("обозначение уже встречалось в строке %d", first),
+				})
+				continue
+			}
+			seen[row.Star.Designation] = row.Line
+
+			var change Change
+			err := tx.Transaction(func(rowTx *gorm.DB) error {
+				var err error
+				change, err = importRow(repository.NewRepositoryFromDB(rowTx), row, dryRun)
+				return err
+			})
+			if err != nil {
+				report.Errors = append(report.Errors, RowError{Line: row.Line, Designation: row.Star.Designation, Error: err.Error()})
+				continue
+			}
+
+			switch change.Action {
+			case ActionCreate:
+				report.Created++
+			case ActionUpdate:
+				report.Updated++
+			case ActionUnchanged:
+				report.Unchanged++
+			}
+			report.Changes = append(report.Changes, change)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func importRow(repo *repository.Repository, row Row, dryRun bool) (Change, error) {
+	change := Change{Line: row.Line, Designation: row.Star.Designation}
+
+	existing, err := repo.GetStarByDesignation(row.Star.Designation)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		change.Action = ActionCreate
+		if dryRun {
+			return change, nil
+		}
+		star := row.Star
+		if err := repo.CreateStar(&star); err != nil {
+			return change, err
+		}
+		change.StarID = star.StarID
+		return change, nil
+	}
+	if err != nil {
+		return change, err
+	}
+
+	change.StarID = existing.StarID
+	oldValues := starColumns(*existing)
+	newValues := starColumns(row.Star)
+
+	updates := map[string]interface{}{}
+	for _, col := range row.Fields {
+		if oldValues[col] != newValues[col] {
+			updates[col] = newValues[col]
+			if change.Fields == nil {
+				change.Fields = map[string]FieldChange{}
+			}
+			change.Fields[col] = FieldChange{Old: oldValues[col], New: newValues[col]}
+		}
+	}
+
+	if len(updates) == 0 {
+		change.Action = ActionUnchanged
+		return change, nil
+	}
+
+	change.Action = ActionUpdate
+	if dryRun {
+		return change, nil
+	}
+	return change, repo.UpdateStarFields(existing.StarID, updates)
+}
+
+// starColumns возвращает значения импортируемых столбцов звезды
+func starColumns(s models.Star) map[string]interface{} {
+	return map[string]interface{}{
+		"designation":       s.Designation,
+		"star_name":         s.StarName,
+		"short_description": s.ShortDescription,
+		"description":       s.Description,
+		"image_url":         s.ImageURL,
+		"ra":                s.RA,
+		"dec":               s.Dec,
+		"epoch":             s.Epoch,
+		"frame":             s.Frame,
+		"pm_ra":             s.PMRA,
+		"pm_dec":            s.PMDec,
+		"parallax":          s.Parallax,
+		"radial_velocity":   s.RadialVelocity,
+		"v_mag":             optionalValue(s.VMag),
+		"b_v":               optionalValue(s.BV),
+		"spectral_type":     s.SpectralType,
+	}
+}
+
+// optionalValue разыменовывает необязательную величину, чтобы значения можно было сравнивать
+func optionalValue(v *float64) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
@@ -8,6 +8,12 @@ import (
 	"github.com/spf13/viper"
 )
 
+// Значения по умолчанию для предупреждения о яркой Луне при формировании заявки
+const (
+	DefaultMoonSeparationLimit   = 30.0 // минимальное расстояние от звезды до Луны, градусы
+	DefaultMoonIlluminationLimit = 0.5  // освещённость, начиная с которой Луна считается яркой
+)
+
 type Config struct {
 	ServiceHost string
 	ServicePort int
@@ -44,3 +50,16 @@ func NewConfig() (*Config, error) {
 
 	return cfg, nil
 }
+
+// MoonWarningLimits возвращает текущие пороги предупреждения о яркой Луне.
+// Значения читаются из конфига при каждом вызове, поэтому изменения файла применяются без перезапуска.
+func MoonWarningLimits() (separation, illumination float64) {
+	separation, illumination = DefaultMoonSeparationLimit, DefaultMoonIlluminationLimit
+	if viper.IsSet("MoonSeparationLimit") {
+		separation = viper.GetFloat64("MoonSeparationLimit")
+	}
+	if viper.IsSet("MoonIlluminationLimit") {
+		illumination = viper.GetFloat64("MoonIlluminationLimit")
+	}
+	return separation, illumination
+}
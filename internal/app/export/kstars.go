@@ -0,0 +1,195 @@
+package export
+
+import (
+	"Lab1/internal/app/models"
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Длительность кадра по умолчанию для последовательностей Ekos, секунды
+const defaultExposure = 60
+
+// Ekos допускает приоритет заданий от 1 до 20
+const maxEkosPriority = 20
+
+// Файл планировщика Ekos (.esl)
+type eslSchedulerList struct {
+	XMLName     xml.Name `xml:"SchedulerList"`
+	Version     string   `xml:"version,attr"`
+	Profile     string   `xml:"Profile"`
+	Jobs        []eslJob `xml:"Job"`
+	Startup     []string `xml:"StartupProcedure>Procedure"`
+	Shutdown    []string `xml:"ShutdownProcedure>Procedure"`
+	ErrorPolicy struct {
+		Value int `xml:"value,attr"`
+		Delay int `xml:"delay"`
+	} `xml:"ErrorHandlingStrategy"`
+}
+
+type eslJob struct {
+	Name        string   `xml:"Name"`
+	Priority    int      `xml:"Priority"`
+	RA          float64  `xml:"Coordinates>J2000RA"` // часы
+	Dec         float64  `xml:"Coordinates>J2000DE"` // градусы
+	Rotation    float64  `xml:"Rotation"`
+	Sequence    string   `xml:"Sequence"`
+	Startup     string   `xml:"StartupCondition>Condition"`
+	Constraints []eslArg `xml:"Constraints>Constraint"`
+	Completion  string   `xml:"CompletionCondition>Condition"`
+	Steps       []string `xml:"Steps>Step"`
+}
+
+type eslArg struct {
+	Value string `xml:"value,attr,omitempty"`
+	Name  string `xml:",chardata"`
+}
+
+// Файл последовательности съёмки Ekos (.esq)
+type esqSequenceQueue struct {
+	XMLName     xml.Name `xml:"SequenceQueue"`
+	Version     string   `xml:"version,attr"`
+	CCD         string   `xml:"CCD"`
+	FilterWheel string   `xml:"FilterWheel"`
+	Observer    string   `xml:"Observer"`
+	GuideDev    esqFlag  `xml:"GuideDeviation"`
+	Autofocus   esqFlag  `xml:"Autofocus"`
+	Jobs        []esqJob `xml:"Job"`
+}
+
+type esqFlag struct {
+	Enabled bool    `xml:"enabled,attr"`
+	Value   float64 `xml:",chardata"`
+}
+
+type esqJob struct {
+	Exposure  float64 `xml:"Exposure"`
+	Format    string  `xml:"Format"`
+	Encoding  string  `xml:"Encoding"`
+	BinX      int     `xml:"Binning>X"`
+	BinY      int     `xml:"Binning>Y"`
+	Filter    string  `xml:"Filter"`
+	Type      string  `xml:"Type"`
+	RawPrefix string  `xml:"Prefix>RawPrefix"`
+	Count     int     `xml:"Count"`
+	Delay     int     `xml:"Delay"`
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9_\-]+`)
+
+// sequenceFileName — имя файла последовательности для цели с номером n
+func sequenceFileName(orderID, n int, star models.Star) string {
+	name := strings.Trim(unsafeFileChars.ReplaceAllString(star.StarName, "_"), "_")
+	if name == "" {
+		name = fmt.Sprintf("star_%d", star.StarID)
+	}
+	return fmt.Sprintf("observation_%d_%02d_%s.esq", orderID, n, name)
+}
+
+// WriteKStars записывает zip-архив для KStars/Ekos: файл планировщика .esl
+// и по одной последовательности .esq на цель. Задания идут в порядке order_number.
+func WriteKStars(w io.Writer, order *models.TelescopeObservation) error {
+	z := zip.NewWriter(w)
+
+	schedule := eslSchedulerList{
+		Version:  "1.4",
+		Profile:  "Default",
+		Startup:  []string{"UnparkMount"},
+		Shutdown: []string{"ParkMount"},
+	}
+	schedule.ErrorPolicy.Value = 1
+
+	targets := sortedTargets(order)
+	for i, s := range targets {
+		seqName := sequenceFileName(order.TelescopeObservationID, i+1, s.Star)
+		priority := ekosPriority(i, len(targets))
+		ra, dec := s.Star.J2000Position()
+
+		schedule.Jobs = append(schedule.Jobs, eslJob{
+			Name:        s.Star.StarName,
+			Priority:    priority,
+			RA:          ra / 15,
+			Dec:         dec,
+			Sequence:    seqName,
+			Startup:     "ASAP",
+			Constraints: []eslArg{{Value: "15", Name: "MinimumAltitude"}, {Name: "EnforceTwilight"}},
+			Completion:  "Sequence",
+			Steps:       []string{"Track", "Focus", "Align", "Guide"},
+		})
+
+		sequence := esqSequenceQueue{
+			Version:     "2.1",
+			CCD:         "CCD Simulator",
+			FilterWheel: "--",
+			GuideDev:    esqFlag{Enabled: false, Value: 2},
+			Autofocus:   esqFlag{Enabled: false, Value: 0},
+			Jobs:        sequenceJobs(s),
+		}
+		if len(s.Exposures) > 0 {
+			sequence.FilterWheel = "Filter Simulator"
+		}
+		if err := writeXMLFile(z, seqName, sequence); err != nil {
+			return err
+		}
+	}
+
+	if err := writeXMLFile(z, fmt.Sprintf("observation_%d.esl", order.TelescopeObservationID), schedule); err != nil {
+		return err
+	}
+	return z.Close()
+}
+
+// ekosPriority переводит позицию цели i из n в приоритет Ekos 1..20 с сохранением порядка:
+// до 20 целей получают приоритеты подряд, при большем числе соседние цели делят приоритет
+// и выполняются в порядке следования в файле планировщика
+func ekosPriority(i, n int) int {
+	return 1 + i*min(n, maxEkosPriority)/n
+}
+
+// sequenceJobs — задания съёмки цели: по одному на шаг последовательности фильтров,
+// без последовательности — Quantity кадров без фильтра с выдержкой по умолчанию
+func sequenceJobs(s models.TelescopeObservationStar) []esqJob {
+	job := esqJob{
+		Exposure:  defaultExposure,
+		Format:    "Mono",
+		Encoding:  "FITS",
+		BinX:      1,
+		BinY:      1,
+		Filter:    "--",
+		Type:      "Light",
+		RawPrefix: s.Star.StarName,
+		Count:     max(s.Quantity, 1),
+	}
+	if len(s.Exposures) == 0 {
+		return []esqJob{job}
+	}
+
+	jobs := make([]esqJob, 0, len(s.Exposures))
+	for _, e := range s.Exposures {
+		step := job
+		step.Exposure = e.ExposureTime
+		step.Count = e.Count
+		if e.Filter != nil {
+			step.Filter = e.Filter.Name
+			step.RawPrefix = s.Star.StarName + "_" + e.Filter.Name
+		}
+		jobs = append(jobs, step)
+	}
+	return jobs
+}
+
+func writeXMLFile(z *zip.Writer, name string, v interface{}) error {
+	f, err := z.Create(name)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(f, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(f)
+	enc.Indent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,105 @@
+package export
+
+import (
+	"Lab1/internal/app/astro"
+	"Lab1/internal/app/calculator"
+	"Lab1/internal/app/models"
+	"fmt"
+)
+
+// Столбцы звезды каталога. Координаты выгружаются в ICRS на эпоху J2000
+// независимо от эпохи и системы, в которых звезда хранится.
+var starColumns = []Column{
+	{Name: "star_id", Datatype: "int", UCD: "meta.id", Description: "Идентификатор звезды в приложении"},
+	{Name: "designation", Datatype: "char", UCD: "meta.id;meta.main", Description: "Каталожное обозначение"},
+	{Name: "star_name", Datatype: "char", UCD: "meta.id", Description: "Название звезды"},
+	{Name: "ra", Datatype: "double", Unit: "deg", UCD: "pos.eq.ra;meta.main", Ref: CooSysICRS, Description: "Прямое восхождение"},
+	{Name: "dec", Datatype: "double", Unit: "deg", UCD: "pos.eq.dec;meta.main", Ref: CooSysICRS, Description: "Склонение"},
+	{Name: "epoch", Datatype: "double", Unit: "yr", UCD: "time.epoch", Description: "Эпоха координат, юлианский год"},
+	{Name: "frame", Datatype: "char", UCD: "meta.code", Description: "Система отсчёта координат"},
+	{Name: "pm_ra", Datatype: "double", Unit: "mas/yr", UCD: "pos.pm;pos.eq.ra", Description: "Собственное движение μα·cos δ"},
+	{Name: "pm_dec", Datatype: "double", Unit: "mas/yr", UCD: "pos.pm;pos.eq.dec", Description: "Собственное движение по склонению"},
+	{Name: "parallax", Datatype: "double", Unit: "mas", UCD: "pos.parallax.trig", Description: "Тригонометрический параллакс"},
+	{Name: "radial_velocity", Datatype: "double", Unit: "km/s", UCD: "spect.dopplerVeloc.opt", Description: "Лучевая скорость"},
+	{Name: "v_mag", Datatype: "double", Unit: "mag", UCD: "phot.mag;em.opt.V", Description: "Звёздная величина V"},
+	{Name: "b_v", Datatype: "double", Unit: "mag", UCD: "phot.color;em.opt.B;em.opt.V", Description: "Показатель цвета B−V"},
+	{Name: "spectral_type", Datatype: "char", UCD: "src.spType", Description: "Спектральный класс"},
+}
+
+func starValues(s models.Star) []interface{} {
+	ra, dec := s.J2000Position()
+	return []interface{}{
+		s.StarID, s.Designation, s.StarName, ra, dec, astro.DefaultEpoch, astro.FrameICRS,
+		s.PMRA, s.PMDec, s.Parallax, s.RadialVelocity,
+		s.VMag, s.BV, s.SpectralType,
+	}
+}
+
+// StarTable — заголовок выгрузки каталога звёзд
+func StarTable() Table {
+	return Table{
+		Name:        "stars",
+		Description: "Каталог звёзд",
+		Columns:     starColumns,
+	}
+}
+
+// StarRow — строка выгрузки каталога
+func StarRow(s models.Star) []interface{} {
+	return starValues(s)
+}
+
+// OrderTable — заголовок выгрузки звёзд заявки с результатами расчёта.
+// calc — калькулятор, которым рассчитаны результаты (nil, если заявка ещё не завершена).
+func OrderTable(order *models.TelescopeObservation, calc calculator.Calculator) Table {
+	result := Column{Name: "result_value", Datatype: "double", Description: "Результат расчёта"}
+	if calc != nil {
+		result.Unit = calc.Unit()
+		result.UCD = calc.UCD()
+		result.Description = fmt.Sprintf("Результат калькулятора %s v%s", calc.Name(), calc.Version())
+	}
+
+	columns := append([]Column{}, starColumns...)
+	columns = append(columns,
+		Column{Name: "order_number", Datatype: "int", UCD: "meta.number", Description: "Порядковый номер цели"},
+		Column{Name: "quantity", Datatype: "int", UCD: "meta.number", Description: "Количество наблюдений"},
+		result,
+		Column{Name: "airmass", Datatype: "double", UCD: "obs.airMass", Description: "Воздушная масса"},
+		Column{Name: "extinction", Datatype: "double", Unit: "mag", UCD: "phys.absorption", Description: "Атмосферная экстинкция"},
+	)
+
+	params := []Param{
+		{Column: Column{Name: "telescope_observation_id", Datatype: "int", UCD: "meta.id"}, Value: order.TelescopeObservationID},
+		{Column: Column{Name: "status", Datatype: "char", UCD: "meta.code.status"}, Value: order.Status},
+		{Column: Column{Name: "observation_date", Datatype: "char", UCD: "time.start;obs"}, Value: order.ObservationDate},
+		{Column: Column{Name: "observer_latitude", Datatype: "double", Unit: "deg", UCD: "pos.earth.lat"}, Value: order.ObserverLatitude},
+		{Column: Column{Name: "observer_longitude", Datatype: "double", Unit: "deg", UCD: "pos.earth.lon"}, Value: order.ObserverLongitude},
+		{Column: Column{Name: "observer_elevation", Datatype: "double", Unit: "m", UCD: "pos.earth.altitude"}, Value: order.Observer().Elevation},
+	}
+	if t := order.Telescope; t != nil {
+		params = append(params,
+			Param{Column: Column{Name: "telescope", Datatype: "char", UCD: "instr.tel"}, Value: t.Name},
+			Param{Column: Column{Name: "telescope_aperture", Datatype: "double", Unit: "mm", UCD: "instr.tel.diameter"}, Value: t.Aperture},
+			Param{Column: Column{Name: "telescope_focal_length", Datatype: "double", Unit: "mm", UCD: "instr.tel.focalLength"}, Value: t.FocalLength},
+		)
+	}
+	if cam := order.Camera; cam != nil {
+		params = append(params, Param{Column: Column{Name: "camera", Datatype: "char", UCD: "instr.det"}, Value: cam.Name})
+	}
+	params = append(params,
+		Param{Column: Column{Name: "calculator", Datatype: "char", UCD: "meta.code"}, Value: order.Calculator},
+		Param{Column: Column{Name: "calculator_version", Datatype: "char", UCD: "meta.version"}, Value: order.CalculatorVersion},
+	)
+
+	return Table{
+		Name:        fmt.Sprintf("observation_%d", order.TelescopeObservationID),
+		Description: "Звёзды заявки на наблюдение и результаты расчёта",
+		Params:      params,
+		Columns:     columns,
+	}
+}
+
+// OrderRow — строка выгрузки звезды заявки
+func OrderRow(s models.TelescopeObservationStar) []interface{} {
+	return append(starValues(s.Star), s.OrderNumber, s.Quantity, s.ResultValue, s.Airmass, s.Extinction)
+}
@@ -0,0 +1,113 @@
+package export
+
+import (
+	"Lab1/internal/app/astro"
+	"Lab1/internal/app/models"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// stellariumObject — объект списка наблюдений Stellarium (плагин Observing list, версия 2.0)
+type stellariumObject struct {
+	Designation     string `json:"designation"`
+	Name            string `json:"name"`
+	NameI18n        string `json:"nameI18n"`
+	Type            string `json:"type"`
+	ObjType         string `json:"objtype"`
+	RA              string `json:"ra"`
+	Dec             string `json:"dec"`
+	Magnitude       string `json:"magnitude"`
+	Constellation   string `json:"constellation"`
+	JD              string `json:"jd"`
+	Location        string `json:"location"`
+	LandscapeID     string `json:"landscapeID"`
+	FOV             int    `json:"fov"`
+	IsVisibleMarker bool   `json:"isVisibleMarker"`
+}
+
+type stellariumList struct {
+	CreationDate string             `json:"creation date"`
+	Description  string             `json:"description"`
+	Name         string             `json:"name"`
+	Objects      []stellariumObject `json:"objects"`
+	Sorting      string             `json:"sorting"`
+}
+
+type stellariumFile struct {
+	DefaultListOLUD string                    `json:"defaultListOlud"`
+	ObservingLists  map[string]stellariumList `json:"observingLists"`
+	ShortName       string                    `json:"shortName"`
+	Version         string                    `json:"version"`
+}
+
+// Stellarium разбирает угловые минуты и секунды в ASCII-записи
+var asciiAngle = strings.NewReplacer("′", "'", "″", `"`)
+
+// sortedTargets возвращает звёзды заявки в порядке order_number
+func sortedTargets(order *models.TelescopeObservation) []models.TelescopeObservationStar {
+	stars := append([]models.TelescopeObservationStar{}, order.TelescopeObservationStars...)
+	sort.SliceStable(stars, func(i, j int) bool { return stars[i].OrderNumber < stars[j].OrderNumber })
+	return stars
+}
+
+// WriteStellarium записывает заявку как список наблюдений Stellarium (observingLists.json).
+// Порядок объектов совпадает с order_number, координаты приводятся к J2000.
+func WriteStellarium(w io.Writer, order *models.TelescopeObservation) error {
+	// Идентификатор списка стабилен для одной и той же заявки
+	olud := "{" + uuid.NewSHA1(uuid.NameSpaceOID, []byte(fmt.Sprintf("telescope-observation-%d", order.TelescopeObservationID))).String() + "}"
+
+	observedAt := order.ObservedAt()
+	jd := fmt.Sprintf("%.6f", astro.JulianDate(observedAt))
+	location := fmt.Sprintf("%.4f, %.4f", order.ObserverLatitude, order.ObserverLongitude)
+
+	objects := []stellariumObject{}
+	for _, s := range sortedTargets(order) {
+		name := s.Star.StarName
+		designation := s.Star.Designation
+		if designation == "" {
+			designation = name
+		}
+		magnitude := ""
+		if s.Star.VMag != nil {
+			magnitude = fmt.Sprintf("%.2f", *s.Star.VMag)
+		}
+		ra, dec := s.Star.J2000Position()
+		objects = append(objects, stellariumObject{
+			Designation: designation,
+			Name:        name,
+			NameI18n:    name,
+			Type:        "Star",
+			ObjType:     "star",
+			RA:          astro.FormatRA(ra),
+			Dec:         asciiAngle.Replace(astro.FormatDec(dec)),
+			Magnitude:   magnitude,
+			JD:          jd,
+			Location:    location,
+		})
+	}
+
+	file := stellariumFile{
+		DefaultListOLUD: olud,
+		ObservingLists: map[string]stellariumList{
+			olud: {
+				CreationDate: time.Now().Format("2006-01-02 15:04:05"),
+				Description:  fmt.Sprintf("Заявка на наблюдение №%d, %s UTC", order.TelescopeObservationID, observedAt.UTC().Format("02.01.2006 15:04")),
+				Name:         fmt.Sprintf("Заявка №%d", order.TelescopeObservationID),
+				Objects:      objects,
+			},
+		},
+		ShortName: "Observing list for Stellarium",
+		Version:   "2.0",
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(file)
+}
@@ -0,0 +1,104 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Поддерживаемые форматы выгрузки
+const (
+	FormatVOTable = "votable"
+	FormatCSV     = "csv"
+)
+
+// Column — описание столбца таблицы
+type Column struct {
+	Name        string
+	Datatype    string // тип VOTable: int, double, char
+	Unit        string // единица измерения VOUnits
+	UCD         string // Unified Content Descriptor IVOA
+	Description string
+	Ref         string // ссылка на систему координат (COOSYS) для координатных столбцов
+}
+
+// Идентификатор системы координат ICRS в VOTable
+const CooSysICRS = "icrs"
+
+// Param — постоянная величина, относящаяся ко всей таблице
+type Param struct {
+	Column
+	Value interface{}
+}
+
+// Table — заголовок выгружаемой таблицы
+type Table struct {
+	Name        string
+	Description string
+	Params      []Param
+	Columns     []Column
+}
+
+// Writer построчно записывает таблицу, не держа её целиком в памяти
+type Writer interface {
+	WriteHeader(t Table) error
+	WriteRow(values []interface{}) error
+	Close() error
+}
+
+// NewWriter создаёт запись таблицы в формате format
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatVOTable:
+		return &votableWriter{w: w}, nil
+	case FormatCSV:
+		return newCSVWriter(w), nil
+	default:
+		return nil, fmt.Errorf("неизвестный формат выгрузки %q (допустимы %s, %s)", format, FormatVOTable, FormatCSV)
+	}
+}
+
+// ContentType возвращает MIME-тип формата
+func ContentType(format string) string {
+	if format == FormatVOTable {
+		return "application/x-votable+xml"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Extension возвращает расширение файла формата
+func Extension(format string) string {
+	if format == FormatVOTable {
+		return "vot"
+	}
+	return "csv"
+}
+
+// formatValue записывает значение ячейки; пустая строка означает отсутствие значения
+func formatValue(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case int:
+		return strconv.Itoa(x)
+	case float64:
+		return strconv.FormatFloat(x, 'g', -1, 64)
+	case *float64:
+		if x == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*x, 'g', -1, 64)
+	case time.Time:
+		return x.UTC().Format(time.RFC3339)
+	case *time.Time:
+		if x == nil {
+			return ""
+		}
+		return x.UTC().Format(time.RFC3339)
+	default:
+		return fmt.Sprint(x)
+	}
+}
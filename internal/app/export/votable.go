@@ -0,0 +1,87 @@
+package export
+
+import (
+	"bufio"
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// votableWriter пишет таблицу в формате VOTable 1.4 с сериализацией TABLEDATA
+type votableWriter struct {
+	w   io.Writer
+	buf *bufio.Writer
+}
+
+func (v *votableWriter) WriteHeader(t Table) error {
+	v.buf = bufio.NewWriter(v.w)
+	b := v.buf
+
+	b.WriteString(xml.Header)
+	b.WriteString(`<VOTABLE version="1.4" xmlns="http://www.ivoa.net/xml/VOTable/v1.3">` + "\n")
+	b.WriteString(`  <RESOURCE>` + "\n")
+	b.WriteString(`    <COOSYS ID="` + CooSysICRS + `" system="ICRS" epoch="J2000"/>` + "\n")
+	b.WriteString(`    <TABLE name="` + escape(t.Name) + `">` + "\n")
+	if t.Description != "" {
+		b.WriteString(`      <DESCRIPTION>` + escape(t.Description) + `</DESCRIPTION>` + "\n")
+	}
+
+	for _, p := range t.Params {
+		b.WriteString(`      <PARAM` + fieldAttrs(p.Column) + ` value="` + escape(formatValue(p.Value)) + `"/>` + "\n")
+	}
+	for _, c := range t.Columns {
+		b.WriteString(`      <FIELD` + fieldAttrs(c))
+		if c.Description != "" {
+			b.WriteString(`><DESCRIPTION>` + escape(c.Description) + `</DESCRIPTION></FIELD>` + "\n")
+		} else {
+			b.WriteString(`/>` + "\n")
+		}
+	}
+
+	b.WriteString(`      <DATA>` + "\n")
+	_, err := b.WriteString(`        <TABLEDATA>` + "\n")
+	return err
+}
+
+func (v *votableWriter) WriteRow(values []interface{}) error {
+	b := v.buf
+	b.WriteString(`          <TR>`)
+	for _, val := range values {
+		b.WriteString(`<TD>` + escape(formatValue(val)) + `</TD>`)
+	}
+	_, err := b.WriteString(`</TR>` + "\n")
+	return err
+}
+
+func (v *votableWriter) Close() error {
+	b := v.buf
+	b.WriteString(`        </TABLEDATA>` + "\n")
+	b.WriteString(`      </DATA>` + "\n")
+	b.WriteString(`    </TABLE>` + "\n")
+	b.WriteString(`  </RESOURCE>` + "\n")
+	b.WriteString(`</VOTABLE>` + "\n")
+	return b.Flush()
+}
+
+func fieldAttrs(c Column) string {
+	attrs := ` name="` + escape(c.Name) + `" datatype="` + c.Datatype + `"`
+	if c.Datatype == "char" {
+		attrs += ` arraysize="*"`
+	}
+	if c.Unit != "" {
+		attrs += ` unit="` + escape(c.Unit) + `"`
+	}
+	if c.UCD != "" {
+		attrs += ` ucd="` + escape(c.UCD) + `"`
+	}
+	if c.Ref != "" {
+		attrs += ` ref="` + escape(c.Ref) + `"`
+	}
+	return attrs
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
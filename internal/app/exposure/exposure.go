@@ -0,0 +1,161 @@
+package exposure
+
+import (
+	"errors"
+	"math"
+)
+
+// Поток фотонов от звезды V = 0 в полосе V над атмосферой, фотонов/с/см²
+// (≈1000 фотонов/с/см²/Å при ширине полосы 880 Å)
+const ZeroPointFlux = 8.8e5
+
+// Доля потока звезды внутри фотометрической апертуры радиусом в одну FWHM гауссова профиля: 1 − 2⁻⁴
+const apertureFraction = 0.9375
+
+// Угловых секунд в радиане
+const arcsecPerRadian = 206264.806
+
+// Параметры по умолчанию, если они не заданы в запросе
+const (
+	DefaultSkyBrightness = 21.0 // яркость фона неба в полосе V, звёздных величин с квадратной секунды
+	DefaultSeeing        = 2.5  // качество изображения (FWHM), угловые секунды
+	DefaultThroughput    = 0.8  // пропускание оптики телескопа
+)
+
+// Camera — параметры приёмника излучения
+type Camera struct {
+	QuantumEfficiency float64 // квантовая эффективность, доля 0..1
+	ReadNoise         float64 // шум считывания, e⁻ на пиксель
+	DarkCurrent       float64 // темновой ток, e⁻/с на пиксель
+	PixelSize         float64 // размер пикселя, мкм
+}
+
+// DefaultCamera — типичная охлаждаемая ПЗС-камера любительского класса
+var DefaultCamera = Camera{
+	QuantumEfficiency: 0.6,
+	ReadNoise:         10,
+	DarkCurrent:       0.1,
+	PixelSize:         9,
+}
+
+// Setup — инструмент и условия наблюдения
+type Setup struct {
+	Aperture              float64 // диаметр объектива, мм
+	FocalLength           float64 // фокусное расстояние, мм
+	Camera                Camera
+	Throughput            float64 // пропускание оптики, доля 0..1
+	SkyBrightness         float64 // яркость фона неба, звёздных величин с квадратной секунды
+	Seeing                float64 // FWHM изображения звезды, угловые секунды
+	ExtinctionCoefficient float64 // коэффициент экстинкции, звёздных величин на единицу воздушной массы
+}
+
+// Target — звезда: величина V вне атмосферы и воздушная масса в момент наблюдения
+type Target struct {
+	Magnitude float64
+	Airmass   float64
+}
+
+// Estimate — результат расчёта выдержки или отношения сигнал/шум
+type Estimate struct {
+	ExposureTime float64 // выдержка, с
+	SNR          float64 // отношение сигнал/шум
+	SignalRate   float64 // сигнал звезды в апертуре, e⁻/с
+	SkyRate      float64 // фон неба, e⁻/с на пиксель
+	Pixels       float64 // число пикселей в фотометрической апертуре
+	PixelScale   float64 // масштаб, угловых секунд на пиксель
+}
+
+// Validate проверяет, что параметров достаточно для расчёта
+func (s Setup) Validate() error {
+	switch {
+	case s.Aperture <= 0:
+		return errors.New("апертура телескопа должна быть положительной")
+	case s.FocalLength <= 0:
+		return errors.New("для расчёта масштаба нужно фокусное расстояние телескопа")
+	case s.Camera.QuantumEfficiency <= 0 || s.Camera.QuantumEfficiency > 1:
+		return errors.New("квантовая эффективность должна быть в интервале (0, 1]")
+	case s.Camera.PixelSize <= 0:
+		return errors.New("размер пикселя должен быть положительным")
+	case s.Camera.ReadNoise < 0 || s.Camera.DarkCurrent < 0:
+		return errors.New("шум считывания и темновой ток не могут быть отрицательными")
+	case s.Throughput <= 0 || s.Throughput > 1:
+		return errors.New("пропускание оптики должно быть в интервале (0, 1]")
+	case s.Seeing <= 0:
+		return errors.New("качество изображения должно быть положительным")
+	case s.ExtinctionCoefficient < 0:
+		return errors.New("коэффициент экстинкции не может быть отрицательным")
+	}
+	return nil
+}
+
+// rates рассчитывает сигнал звезды, фон неба и размер апертуры для уравнения ПЗС
+func (s Setup) rates(t Target) (Estimate, error) {
+	if err := s.Validate(); err != nil {
+		return Estimate{}, err
+	}
+	if t.Airmass < 1 {
+		return Estimate{}, errors.New("воздушная масса не может быть меньше 1")
+	}
+
+	radius := s.Aperture / 20 // см
+	collected := math.Pi * radius * radius * s.Throughput * s.Camera.QuantumEfficiency * ZeroPointFlux
+
+	scale := arcsecPerRadian * s.Camera.PixelSize / 1000 / s.FocalLength
+	pixels := math.Max(1, math.Pi*s.Seeing*s.Seeing/(scale*scale))
+
+	observed := t.Magnitude + t.Airmass*s.ExtinctionCoefficient
+	return Estimate{
+		SignalRate: collected * apertureFraction * math.Pow(10, -0.4*observed),
+		SkyRate:    collected * math.Pow(10, -0.4*s.SkyBrightness) * scale * scale,
+		Pixels:     pixels,
+		PixelScale: scale,
+	}, nil
+}
+
+// SNR рассчитывает отношение сигнал/шум звезды за выдержку exposure секунд по уравнению ПЗС:
+// SNR = S·t / √(S·t + n·(B·t + D·t + R²))
+func (s Setup) SNR(t Target, exposure float64) (Estimate, error) {
+	if exposure <= 0 {
+		return Estimate{}, errors.New("выдержка должна быть положительной")
+	}
+	e, err := s.rates(t)
+	if err != nil {
+		return Estimate{}, err
+	}
+
+	signal := e.SignalRate * exposure
+	noise := math.Sqrt(signal + e.Pixels*((e.SkyRate+s.Camera.DarkCurrent)*exposure+s.Camera.ReadNoise*s.Camera.ReadNoise))
+	e.ExposureTime = exposure
+	e.SNR = signal / noise
+	if math.IsInf(e.SNR, 0) || math.IsNaN(e.SNR) {
+		return Estimate{}, errors.New("отношение сигнал/шум не удалось рассчитать для заданных параметров")
+	}
+	return e, nil
+}
+
+// ExposureTime рассчитывает выдержку, за которую звезда достигает отношения сигнал/шум snr:
+// положительный корень квадратного уравнения S²·t² − SNR²·(S + n·(B + D))·t − SNR²·n·R² = 0
+func (s Setup) ExposureTime(t Target, snr float64) (Estimate, error) {
+	if snr <= 0 {
+		return Estimate{}, errors.New("отношение сигнал/шум должно быть положительным")
+	}
+	e, err := s.rates(t)
+	if err != nil {
+		return Estimate{}, err
+	}
+
+	if e.SignalRate == 0 {
+		return Estimate{}, errors.New("звезда слишком слаба для расчёта выдержки")
+	}
+
+	snr2 := snr * snr
+	a := e.SignalRate * e.SignalRate
+	b := snr2 * (e.SignalRate + e.Pixels*(e.SkyRate+s.Camera.DarkCurrent))
+	c := snr2 * e.Pixels * s.Camera.ReadNoise * s.Camera.ReadNoise
+	e.ExposureTime = (b + math.Sqrt(b*b+4*a*c)) / (2 * a)
+	if math.IsInf(e.ExposureTime, 0) || math.IsNaN(e.ExposureTime) {
+		return Estimate{}, errors.New("выдержку не удалось рассчитать для заданных параметров")
+	}
+	e.SNR = snr
+	return e, nil
+}
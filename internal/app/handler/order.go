@@ -1,9 +1,11 @@
 package handler
 
 import (
+	"Lab1/internal/app/calculator"
 	"Lab1/internal/app/models"
+	"Lab1/internal/app/planner"
+	"Lab1/internal/app/repository"
 	"fmt"
-	"math"
 	"net/http"
 	"strconv"
 	"time"
@@ -56,9 +58,15 @@ func (h *Handler) GetOrder(ctx *gin.Context) {
 	// Загружаем заявку с привязкой звёзд через TelescopeObservationStars
 	var order models.TelescopeObservation
 	err = h.Repository.DB.
+		Preload("TelescopeObservationStars", repository.ByOrderNumber).
 		Preload("TelescopeObservationStars.Star").
+		Preload("TelescopeObservationStars.Exposures", repository.ByPosition).
+		Preload("TelescopeObservationStars.Exposures.Filter").
 		Preload("Creator").
 		Preload("Moderator").
+		Preload("Site").
+		Preload("Telescope").
+		Preload("Camera").
 		First(&order, id).Error
 	if err != nil {
 		logrus.Error("Ошибка получения корзины: ", err)
@@ -71,6 +79,12 @@ func (h *Handler) GetOrder(ctx *gin.Context) {
 		return
 	}
 
+	order.FillObservationTime()
+	order.FillVisibility()
+	order.FillSky()
+	duration := planner.EstimateDuration(&order)
+	order.EstimatedDuration = &duration
+
 	ctx.HTML(http.StatusOK, "shoppingCartPageWithApplications.html", gin.H{
 		"order": order,
 	})
@@ -168,10 +182,15 @@ func (h *Handler) CompleteOrder(ctx *gin.Context) {
 		return
 	}
 
-	// вычисляем результат для каждой звезды
-	for _, obsStar := range order.TelescopeObservationStars {
-		result := h.calculateResult(&obsStar, obsStar.Star)
-		err := h.Repository.UpdateObservationStarResult(order.TelescopeObservationID, obsStar.Star.StarID, result)
+	// вычисляем результат для каждой звезды калькулятором заявки
+	results, err := calculator.Complete(order, order.TelescopeObservationStars)
+	if err != nil {
+		ctx.String(http.StatusBadRequest, "Неизвестный калькулятор заявки")
+		return
+	}
+
+	for _, res := range results {
+		err := h.Repository.UpdateObservationStarResult(order.TelescopeObservationID, res.StarID, res.Value, res.Airmass, res.Extinction)
 		if err != nil {
 			ctx.String(http.StatusInternalServerError, "Ошибка сохранения результата")
 			return
@@ -190,15 +209,3 @@ func (h *Handler) CompleteOrder(ctx *gin.Context) {
 
 	ctx.Redirect(http.StatusSeeOther, fmt.Sprintf("/order/%d", order.TelescopeObservationID))
 }
-
-func (h *Handler) calculateResult(obsStar *models.TelescopeObservationStar, star models.Star) float64 {
-	// Берем широту и долготу из родительской заявки
-	obs := obsStar.TelescopeObservation
-	delta := math.Abs(obs.ObserverLatitude - obs.ObserverLongitude)
-
-	// Пример формулы:
-	// "Результат" = √(RA² + Dec²) * (1 + |широта - долгота| / 180)
-	value := math.Sqrt(math.Pow(star.RA, 2)+math.Pow(star.Dec, 2)) * (1 + delta/180)
-
-	return math.Round(value*100) / 100 // округляем до сотых
-}
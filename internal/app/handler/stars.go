@@ -3,9 +3,11 @@ package handler
 import (
 	"Lab1/internal/app/auth"
 	"Lab1/internal/app/models"
+	"Lab1/internal/app/planner"
 	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
@@ -59,10 +61,17 @@ func (h *Handler) AddStarToDraftOrder(ctx *gin.Context) {
 
 	// Добавляем новую связь
 	if errors.Is(err, gorm.ErrRecordNotFound) {
+		orderNumber, numErr := h.Repository.NextOrderNumber(order.TelescopeObservationID)
+		if numErr != nil {
+			logrus.Error("Ошибка получения порядкового номера: ", numErr)
+			ctx.String(http.StatusInternalServerError, "Ошибка добавления звезды в корзину")
+			return
+		}
+
 		relation := models.TelescopeObservationStar{
 			TelescopeObservationID: order.TelescopeObservationID,
 			StarID:                 starID,
-			OrderNumber:            1,
+			OrderNumber:            orderNumber,
 			Quantity:               1,
 		}
 
@@ -108,20 +117,112 @@ func (h *Handler) GetStars(ctx *gin.Context) {
 		return
 	}
 
+	sites, err := h.Repository.GetSites()
+	if err != nil {
+		logrus.Error("Ошибка получения мест наблюдения: ", err)
+	}
+
+	// Фильтр «видны этой ночью»: включается выбором места наблюдения
+	visible := gin.H{
+		"site":    ctx.Query("site"),
+		"from":    ctx.Query("from"),
+		"to":      ctx.Query("to"),
+		"min_alt": ctx.Query("min_alt"),
+	}
+	peaks := map[int]*planner.VisibleStar{}
+	if siteID, convErr := strconv.Atoi(ctx.Query("site")); convErr == nil {
+		stars, peaks, err = h.filterVisibleStars(ctx, siteID, stars)
+		if err != nil {
+			ctx.String(http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	// Фотометрия: звёзды ярче vmag_max, сортировка по величине, цвету или спектральному классу
+	photometry := gin.H{
+		"vmag_max": ctx.Query("vmag_max"),
+		"sort":     ctx.Query("sort"),
+	}
+	if raw := ctx.Query("vmag_max"); raw != "" {
+		vmagMax, convErr := strconv.ParseFloat(raw, 64)
+		if convErr != nil {
+			ctx.String(http.StatusBadRequest, "Некорректная звёздная величина")
+			return
+		}
+		stars = filterBrighterThan(stars, vmagMax)
+	}
+	sortKey, descending, err := models.ParseStarSort(ctx.Query("sort"))
+	if err != nil {
+		ctx.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	models.SortStars(stars, sortKey, descending)
+
 	hasDraft, draftID, cartCount, err := h.Repository.GetCartInfo(userID)
 	if err != nil {
 		logrus.Error("Ошибка получения информации о корзине: ", err)
 	}
 
 	ctx.HTML(http.StatusOK, "pageStars.html", gin.H{
-		"stars":     stars,
-		"query":     query,
-		"hasDraft":  hasDraft,
-		"draftID":   draftID,
-		"cartCount": cartCount,
+		"stars":      stars,
+		"query":      query,
+		"sites":      sites,
+		"visible":    visible,
+		"peaks":      peaks,
+		"photometry": photometry,
+		"hasDraft":   hasDraft,
+		"draftID":    draftID,
+		"cartCount":  cartCount,
 	})
 }
 
+// filterBrighterThan оставляет звёзды с известной величиной V не слабее vmagMax
+func filterBrighterThan(stars []models.Star, vmagMax float64) []models.Star {
+	result := make([]models.Star, 0, len(stars))
+	for _, s := range stars {
+		if s.VMag != nil && *s.VMag <= vmagMax {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// filterVisibleStars оставляет звёзды, поднимающиеся выше min_alt в промежутке from–to
+// над местом siteID, и упорядочивает их по убыванию наибольшей высоты
+func (h *Handler) filterVisibleStars(ctx *gin.Context, siteID int, stars []models.Star) ([]models.Star, map[int]*planner.VisibleStar, error) {
+	site, err := h.Repository.GetSiteByID(siteID)
+	if err != nil {
+		return nil, nil, errors.New("Место наблюдения не найдено")
+	}
+
+	window, err := planner.ParseWindow(site.Observer(), site.Location(), ctx.Query("from"), ctx.Query("to"), time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	minAltitude := 0.0
+	if text := ctx.Query("min_alt"); text != "" {
+		if minAltitude, err = strconv.ParseFloat(text, 64); err != nil {
+			return nil, nil, errors.New("Минимальная высота должна быть числом")
+		}
+	}
+
+	visibility := planner.NewVisibility(site.Observer(), window, minAltitude)
+	for _, star := range stars {
+		visibility.Add(star)
+	}
+
+	filtered := []models.Star{}
+	peaks := map[int]*planner.VisibleStar{}
+	for _, v := range visibility.Stars() {
+		v := v
+		v.PeakTime = v.PeakTime.In(site.Location())
+		filtered = append(filtered, v.Star)
+		peaks[v.Star.StarID] = &v
+	}
+	return filtered, peaks, nil
+}
+
 func (h *Handler) GetStarByID(ctx *gin.Context) {
 	idStr := ctx.Param("id")
 	starID, err := strconv.Atoi(idStr)
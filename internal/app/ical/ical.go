@@ -0,0 +1,102 @@
+package ical
+
+import (
+	"Lab1/internal/app/astro"
+	"Lab1/internal/app/models"
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const dateTimeFormat = "20060102T150405Z"
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+// WriteCalendar записывает календарь iCalendar (RFC 5545): по событию на заявку,
+// событие охватывает окно видимости её звёзд
+func WriteCalendar(w io.Writer, name string, orders []models.TelescopeObservation) error {
+	b := bufio.NewWriter(w)
+	now := time.Now().UTC().Format(dateTimeFormat)
+
+	writeLine(b, "BEGIN:VCALENDAR")
+	writeLine(b, "VERSION:2.0")
+	writeLine(b, "PRODID:-//Telescope Calculator//Observations//RU")
+	writeLine(b, "CALSCALE:GREGORIAN")
+	writeLine(b, "METHOD:PUBLISH")
+	writeLine(b, "X-WR-CALNAME:"+escapeText(name))
+	writeLine(b, "X-PUBLISHED-TTL:PT1H")
+
+	for i := range orders {
+		order := &orders[i]
+		order.FillVisibility()
+		start, end := order.VisibilityWindow()
+
+		writeLine(b, "BEGIN:VEVENT")
+		writeLine(b, fmt.Sprintf("UID:telescope-observation-%d@telescope-calculator", order.TelescopeObservationID))
+		writeLine(b, "DTSTAMP:"+now)
+		writeLine(b, "DTSTART:"+start.UTC().Format(dateTimeFormat))
+		writeLine(b, "DTEND:"+end.UTC().Format(dateTimeFormat))
+		writeLine(b, "SUMMARY:"+escapeText(fmt.Sprintf("Наблюдение №%d (%s)", order.TelescopeObservationID, order.Status)))
+		writeLine(b, "DESCRIPTION:"+escapeText(describe(order)))
+		writeLine(b, fmt.Sprintf("GEO:%.6f;%.6f", order.ObserverLatitude, order.ObserverLongitude))
+		writeLine(b, "STATUS:"+eventStatus(order.Status))
+		writeLine(b, "END:VEVENT")
+	}
+
+	writeLine(b, "END:VCALENDAR")
+	return b.Flush()
+}
+
+// describe перечисляет звёзды заявки со временем кульминации
+func describe(order *models.TelescopeObservation) string {
+	var lines []string
+	for _, s := range order.TelescopeObservationStars {
+		line := fmt.Sprintf("%s: RA %s, Dec %s", s.Star.StarName, astro.FormatRA(s.Star.RA), astro.FormatDec(s.Star.Dec))
+		if s.Visibility != nil {
+			switch {
+			case s.Visibility.NeverRises:
+				line += " — не восходит"
+			default:
+				line += ", кульминация " + s.Visibility.Transit.UTC().Format("15:04") + " UTC"
+			}
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return "В заявке нет звёзд"
+	}
+	return "Звёзды:\n" + strings.Join(lines, "\n")
+}
+
+func eventStatus(status string) string {
+	if status == "завершён" {
+		return "CONFIRMED"
+	}
+	return "TENTATIVE"
+}
+
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+// writeLine записывает строку с переносом длинных строк по 75 октетов (RFC 5545, 3.1),
+// не разрывая символы UTF-8
+func writeLine(b *bufio.Writer, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		limit = 74 // продолжение начинается с пробела
+	}
+	b.WriteString(line + "\r\n")
+}
+
+func isRuneStart(c byte) bool {
+	return c&0xC0 != 0x80
+}
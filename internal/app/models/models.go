@@ -1,6 +1,15 @@
 package models
 
-import "time"
+import (
+	"Lab1/internal/app/astro"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type User struct {
 	UserID       int    `gorm:"column:user_id;primaryKey"`
@@ -12,17 +21,89 @@ type User struct {
 type Star struct {
 	StarID           int     `gorm:"primaryKey;autoIncrement;column:star_id"`
 	StarName         string  `gorm:"column:star_name"`
+	Designation      string  `gorm:"column:designation;uniqueIndex:idx_stars_designation,where:designation <> ''"` // каталожное обозначение, например "HR 1713"
 	ShortDescription string  `gorm:"column:short_description"`
 	Description      string  `gorm:"column:description"`
 	ImageURL         string  `gorm:"column:image_url"`
 	IsActive         bool    `gorm:"column:is_active"`
-	RA               float64 `gorm:"column:ra"`
-	Dec              float64 `gorm:"column:dec"`
+	RA               float64 `gorm:"column:ra"`                 // прямое восхождение, градусы
+	Dec              float64 `gorm:"column:dec;index"`          // склонение, градусы
+	Epoch            float64 `gorm:"column:epoch;default:2000"` // эпоха каталога, юлианский год
+	Frame            string  `gorm:"column:frame;default:ICRS"` // система отсчёта координат
+	PMRA             float64 `gorm:"column:pm_ra"`              // собственное движение μα·cos δ, мсд/год
+	PMDec            float64 `gorm:"column:pm_dec"`             // собственное движение по склонению, мсд/год
+	Parallax         float64 `gorm:"column:parallax"`           // параллакс, мсд
+	RadialVelocity   float64 `gorm:"column:radial_velocity"`    // лучевая скорость, км/с
+
+	// пиксель HEALPix порядка StarHEALPixOrder для положения на J2000; ключ поиска в конусе
+	HEALPix *int64 `gorm:"column:healpix;index"`
+
+	// фотометрия; NULL — величина в каталоге не указана
+	VMag         *float64 `gorm:"column:v_mag;index"`   // видимая звёздная величина V
+	BV           *float64 `gorm:"column:b_v"`           // показатель цвета B−V
+	SpectralType string   `gorm:"column:spectral_type"` // спектральный класс MK, например "G2V"
+
+	// рассчитываются при выдаче звезды, в БД не хранятся
+	Galactic *astro.Galactic `gorm:"-"`
+	Ecliptic *astro.Ecliptic `gorm:"-"`
 
 	// связь многие-ко-многим через telescope_observation_stars
 	Observations []TelescopeObservation `gorm:"many2many:telescope_observation_stars;foreignKey:StarID;joinForeignKey:star_id;References:TelescopeObservationID;joinReferences:telescope_observation_id"`
 }
 
+// ObservingSite — место наблюдения (обсерватория, купол, выездная площадка)
+type ObservingSite struct {
+	SiteID       int     `gorm:"primaryKey;column:site_id"`
+	Name         string  `gorm:"column:name;not null"`
+	Latitude     float64 `gorm:"column:latitude"`                 // широта, градусы
+	Longitude    float64 `gorm:"column:longitude"`                // долгота, градусы, восток — положительная
+	Elevation    float64 `gorm:"column:elevation"`                // высота над уровнем моря, метры
+	Timezone     string  `gorm:"column:timezone;default:UTC"`     // часовой пояс IANA, например "Europe/Moscow"
+	HorizonLimit float64 `gorm:"column:horizon_limit"`            // минимальная высота наведения, градусы
+	IsDefault    bool    `gorm:"column:is_default;default:false"` // место по умолчанию для новых черновиков
+}
+
+// Типы монтировок телескопа
+const (
+	MountAltAz      = "alt-az"
+	MountEquatorial = "equatorial"
+	MountFork       = "fork"
+)
+
+// Telescope — инструмент, на который подаётся заявка
+type Telescope struct {
+	TelescopeID       int     `gorm:"primaryKey;column:telescope_id"`
+	Name              string  `gorm:"column:name;not null"`
+	Aperture          float64 `gorm:"column:aperture"`           // диаметр объектива, мм
+	FocalLength       float64 `gorm:"column:focal_length"`       // фокусное расстояние, мм
+	MountType         string  `gorm:"column:mount_type"`         // alt-az, equatorial, fork
+	SlewRate          float64 `gorm:"column:slew_rate"`          // скорость наведения, градусов в секунду
+	FieldOfView       float64 `gorm:"column:field_of_view"`      // поле зрения, угловые минуты
+	LimitingMagnitude float64 `gorm:"column:limiting_magnitude"` // предельная звёздная величина в зените
+}
+
+// Camera — камера, которой ведётся съёмка
+type Camera struct {
+	CameraID          int     `gorm:"primaryKey;column:camera_id"`
+	Name              string  `gorm:"column:name;not null"`
+	QuantumEfficiency float64 `gorm:"column:quantum_efficiency"` // квантовая эффективность, доля 0..1
+	ReadNoise         float64 `gorm:"column:read_noise"`         // шум считывания, e⁻ на пиксель
+	DarkCurrent       float64 `gorm:"column:dark_current"`       // темновой ток, e⁻/с на пиксель
+	PixelSize         float64 `gorm:"column:pixel_size"`         // размер пикселя, мкм
+	Width             int     `gorm:"column:width"`              // размер кадра, пиксели
+	Height            int     `gorm:"column:height"`
+	ReadoutTime       float64 `gorm:"column:readout_time"` // считывание и запись кадра, с
+}
+
+// Filter — светофильтр из набора обсерватории
+type Filter struct {
+	FilterID   int     `gorm:"primaryKey;column:filter_id"`
+	Name       string  `gorm:"column:name;not null;uniqueIndex"` // обозначение полосы: "B", "V", "Rc", "Ha"
+	System     string  `gorm:"column:system"`                    // фотометрическая система, например "Johnson-Cousins"
+	Wavelength float64 `gorm:"column:wavelength"`                // центральная длина волны, нм
+	Bandwidth  float64 `gorm:"column:bandwidth"`                 // ширина полосы пропускания, нм
+}
+
 type TelescopeObservation struct {
 	TelescopeObservationID int        `gorm:"primaryKey;column:telescope_observation_id"`
 	CreatorID              int        `gorm:"column:creator_id"`
@@ -32,24 +113,483 @@ type TelescopeObservation struct {
 	FormationDate          *time.Time `gorm:"column:formation_date"`
 	CompletionDate         *time.Time `gorm:"column:completion_date"`
 
-	ObservationDate   *time.Time `gorm:"column:observation_date"`
+	ObservationDate   *time.Time `gorm:"column:observation_date;type:timestamptz"` // момент наблюдения, хранится в UTC
 	ObserverLatitude  float64    `gorm:"column:observer_latitude"`
 	ObserverLongitude float64    `gorm:"column:observer_longitude"`
+	SiteID            *int       `gorm:"column:site_id"`
+	TelescopeID       *int       `gorm:"column:telescope_id"`
+	CameraID          *int       `gorm:"column:camera_id"`
 
-	Creator   User  `gorm:"foreignKey:CreatorID;references:UserID"`
-	Moderator *User `gorm:"foreignKey:ModeratorID;references:UserID"`
+	// калькулятор, которым рассчитаны результаты заявки
+	Calculator        string `gorm:"column:calculator"`
+	CalculatorVersion string `gorm:"column:calculator_version"`
+
+	Creator   User           `gorm:"foreignKey:CreatorID;references:UserID"`
+	Moderator *User          `gorm:"foreignKey:ModeratorID;references:UserID"`
+	Site      *ObservingSite `gorm:"foreignKey:SiteID;references:SiteID"`
+	Telescope *Telescope     `gorm:"foreignKey:TelescopeID;references:TelescopeID"`
+	Camera    *Camera        `gorm:"foreignKey:CameraID;references:CameraID"`
+
+	// рассчитываются при выдаче заявки, в БД не хранятся
+	ObservationTime   *ObservationTime     `gorm:"-"`
+	Sky               *astro.Sky           `gorm:"-"`
+	EstimatedDuration *ObservationDuration `gorm:"-"`
 
 	Stars                     []Star                     `gorm:"many2many:telescope_observation_stars;foreignKey:TelescopeObservationID;joinForeignKey:telescope_observation_id;References:StarID;joinReferences:star_id"`
 	TelescopeObservationStars []TelescopeObservationStar `gorm:"foreignKey:TelescopeObservationID"`
 }
 
+// ObservationTime — момент наблюдения в часовом поясе места и связанные с ним шкалы времени
+type ObservationTime struct {
+	UTC                  time.Time
+	Local                time.Time // тот же момент по местному времени места наблюдения
+	Timezone             string    // часовой пояс IANA
+	UTCOffset            string    // смещение от UTC на момент наблюдения, например "+03:00"
+	JulianDate           float64
+	LocalSiderealTime    float64 // местное звёздное время, часы
+	LocalSiderealTimeHMS string
+}
+
+// ObservationDuration — оценка продолжительности наблюдений заявки, секунды
+type ObservationDuration struct {
+	Exposure float64 // суммарная выдержка кадров
+	Readout  float64 // считывание кадров
+	Overhead float64 // наведение между звёздами и успокоение монтировки
+	Total    float64
+}
+
 type TelescopeObservationStar struct {
-	TelescopeObservationID int      `gorm:"primaryKey;column:telescope_observation_id"`
-	StarID                 int      `gorm:"primaryKey;column:star_id"`
-	OrderNumber            int      `gorm:"column:order_number"`
-	Quantity               int      `gorm:"column:quantity"`
-	ResultValue            *float64 `gorm:"column:result_value"`
+	TelescopeObservationID int        `gorm:"primaryKey;column:telescope_observation_id"`
+	StarID                 int        `gorm:"primaryKey;column:star_id"`
+	OrderNumber            int        `gorm:"column:order_number"`
+	Quantity               int        `gorm:"column:quantity"`
+	ResultValue            *float64   `gorm:"column:result_value"`
+	Airmass                *float64   `gorm:"column:airmass"`                        // воздушная масса на момент наблюдения
+	Extinction             *float64   `gorm:"column:extinction"`                     // ослабление блеска, звёздные величины
+	PlannedStart           *time.Time `gorm:"column:planned_start;type:timestamptz"` // начало наблюдения по расписанию
+	ExposureTime           *float64   `gorm:"column:exposure_time"`                  // плановая выдержка, с
+	SNR                    *float64   `gorm:"column:snr"`                            // ожидаемое отношение сигнал/шум
+
+	// рассчитываются при выдаче заявки, в БД не хранятся
+	Visibility     *astro.Visibility `gorm:"-"`
+	MoonSeparation *float64          `gorm:"-"` // угловое расстояние до Луны, градусы
+
+	// последовательность съёмки по фильтрам, упорядоченная по Position
+	Exposures []ObservationExposure `gorm:"foreignKey:TelescopeObservationID,StarID;references:TelescopeObservationID,StarID"`
 
 	TelescopeObservation TelescopeObservation `gorm:"foreignKey:TelescopeObservationID;references:TelescopeObservationID"`
 	Star                 Star                 `gorm:"foreignKey:StarID;references:StarID"`
 }
+
+// ObservationExposure — шаг последовательности съёмки звезды заявки: Count кадров с фильтром
+type ObservationExposure struct {
+	ObservationExposureID  int     `gorm:"primaryKey;column:observation_exposure_id"`
+	TelescopeObservationID int     `gorm:"column:telescope_observation_id;index:idx_observation_exposures_target"`
+	StarID                 int     `gorm:"column:star_id;index:idx_observation_exposures_target"`
+	Position               int     `gorm:"column:position"` // порядок шага в последовательности
+	FilterID               int     `gorm:"column:filter_id;index"`
+	Count                  int     `gorm:"column:count"`         // число кадров
+	ExposureTime           float64 `gorm:"column:exposure_time"` // выдержка кадра, с
+
+	Filter *Filter `gorm:"foreignKey:FilterID;references:FilterID"`
+}
+
+// SequenceDuration возвращает суммарную выдержку и время считывания кадров последовательности
+// съёмки звезды, секунды; readout — считывание одного кадра
+func (s *TelescopeObservationStar) SequenceDuration(readout float64) (exposure, readoutTotal float64) {
+	for _, e := range s.Exposures {
+		exposure += float64(e.Count) * e.ExposureTime
+		readoutTotal += float64(e.Count) * readout
+	}
+	return exposure, readoutTotal
+}
+
+// Text возвращает продолжительность для показа: "2 ч 05 мин", "12 мин" или "40 с"
+func (d ObservationDuration) Text() string {
+	total := int(math.Round(d.Total))
+	switch {
+	case total < 60:
+		return fmt.Sprintf("%d с", total)
+	case total < 3600:
+		return fmt.Sprintf("%d мин", int(math.Round(d.Total/60)))
+	default:
+		minutes := int(math.Round(d.Total / 60))
+		return fmt.Sprintf("%d ч %02d мин", minutes/60, minutes%60)
+	}
+}
+
+// Motion возвращает параметры пространственного движения звезды
+func (s *Star) Motion() astro.Motion {
+	return astro.Motion{
+		PMRA:           s.PMRA,
+		PMDec:          s.PMDec,
+		Parallax:       s.Parallax,
+		RadialVelocity: s.RadialVelocity,
+	}
+}
+
+// ApparentPosition возвращает видимые координаты звезды (градусы) на момент t:
+// каталожное положение переносится на эпоху наблюдения с учётом собственного
+// движения, затем приводится к видимому месту
+func (s *Star) ApparentPosition(t time.Time) (ra, dec float64) {
+	ra, dec = astro.Propagate(s.RA, s.Dec, s.Motion(), s.Epoch, t)
+	return astro.ApparentPlace(ra, dec, s.Epoch, t)
+}
+
+// Момент J2000.0, на который приводятся координаты при выгрузке
+var j2000 = time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// J2000Position возвращает координаты звезды (градусы) в ICRS на эпоху и равноденствие J2000:
+// положение переносится с эпохи каталога с учётом собственного движения и прецессии.
+// FK5 на точности расчётов приложения совпадает с ICRS.
+func (s *Star) J2000Position() (ra, dec float64) {
+	if s.Epoch == 0 || s.Epoch == astro.DefaultEpoch {
+		return s.RA, s.Dec
+	}
+	ra, dec = astro.Propagate(s.RA, s.Dec, s.Motion(), s.Epoch, j2000)
+	return astro.Precess(ra, dec, astro.JulianEpochToJD(s.Epoch), astro.J2000)
+}
+
+// Порядок пикселей HEALPix звёзд каталога: nside = 1024, пиксель около 3.4′
+const StarHEALPixOrder = 10
+
+// StarPositionColumns — столбцы, от которых зависит положение звезды на J2000
+var StarPositionColumns = []string{"ra", "dec", "epoch", "pm_ra", "pm_dec", "parallax", "radial_velocity"}
+
+// FillHEALPix рассчитывает пиксель HEALPix звезды по положению на J2000
+func (s *Star) FillHEALPix() {
+	ra, dec := s.J2000Position()
+	pix := astro.HEALPixNest(StarHEALPixOrder, ra, dec)
+	s.HEALPix = &pix
+}
+
+// BeforeSave пересчитывает пиксель HEALPix при создании и сохранении звезды целиком
+func (s *Star) BeforeSave(tx *gorm.DB) error {
+	s.FillHEALPix()
+	return nil
+}
+
+// HasSpectralClass проверяет, что спектральный класс звезды начинается с prefix
+// ("G" — все звёзды класса G, "K0" — подкласс K0), регистр не учитывается
+func (s *Star) HasSpectralClass(prefix string) bool {
+	return prefix != "" && strings.HasPrefix(strings.ToUpper(strings.TrimSpace(s.SpectralType)), strings.ToUpper(prefix))
+}
+
+// Поля, по которым можно сортировать список звёзд
+var StarSortKeys = map[string]bool{
+	"v_mag":         true,
+	"b_v":           true,
+	"spectral_type": true,
+	"star_name":     true,
+}
+
+// ParseStarSort разбирает параметр сортировки списка звёзд: ключ из StarSortKeys,
+// "-" в начале — по убыванию
+func ParseStarSort(text string) (key string, descending bool, err error) {
+	key = text
+	if strings.HasPrefix(key, "-") {
+		key, descending = key[1:], true
+	}
+	if key != "" && !StarSortKeys[key] {
+		return "", false, fmt.Errorf("Параметр sort должен быть v_mag, b_v, spectral_type или star_name")
+	}
+	return key, descending, nil
+}
+
+// SortStars упорядочивает звёзды по полю key из StarSortKeys; звёзды без значения — в конце списка
+func SortStars(stars []Star, key string, descending bool) {
+	if !StarSortKeys[key] {
+		return
+	}
+
+	less := func(a, b Star) (less, known bool) {
+		switch key {
+		case "v_mag":
+			return optionalLess(a.VMag, b.VMag)
+		case "b_v":
+			return optionalLess(a.BV, b.BV)
+		case "spectral_type":
+			ta, tb := strings.TrimSpace(a.SpectralType), strings.TrimSpace(b.SpectralType)
+			if ta == "" || tb == "" {
+				return ta != "", false
+			}
+			return spectralRank(ta) < spectralRank(tb), true
+		default:
+			return strings.ToLower(a.StarName) < strings.ToLower(b.StarName), true
+		}
+	}
+
+	sort.SliceStable(stars, func(i, j int) bool {
+		if descending {
+			l, known := less(stars[j], stars[i])
+			if !known {
+				l, _ = less(stars[i], stars[j])
+			}
+			return l
+		}
+		l, _ := less(stars[i], stars[j])
+		return l
+	})
+}
+
+// optionalLess сравнивает необязательные величины; known = false, если хотя бы одна не задана,
+// тогда less означает «a задана, b нет»
+func optionalLess(a, b *float64) (less, known bool) {
+	if a == nil || b == nil {
+		return a != nil, false
+	}
+	return *a < *b, true
+}
+
+// Последовательность спектральных классов от горячих звёзд к холодным
+const spectralSequence = "OBAFGKM"
+
+// spectralRank переводит спектральный класс в число для сортировки по температуре:
+// "B2" раньше "A0", пустые классы и классы вне последовательности OBAFGKM — в конце
+func spectralRank(spType string) float64 {
+	spType = strings.ToUpper(strings.TrimSpace(spType))
+	if spType == "" {
+		return float64(len(spectralSequence)) * 10
+	}
+	i := strings.IndexByte(spectralSequence, spType[0])
+	if i < 0 {
+		return float64(len(spectralSequence)) * 10
+	}
+	sub := 0.0
+	if len(spType) > 1 && spType[1] >= '0' && spType[1] <= '9' {
+		sub = float64(spType[1] - '0')
+	}
+	return float64(i)*10 + sub
+}
+
+// FillFrames рассчитывает галактические и эклиптические (J2000) координаты звезды
+func (s *Star) FillFrames() {
+	ra, dec := s.J2000Position()
+
+	gal := astro.EquatorialToGalactic(ra, dec)
+	ecl := astro.EquatorialToEcliptic(ra, dec, astro.ObliquityJ2000)
+	s.Galactic = &gal
+	s.Ecliptic = &ecl
+}
+
+// Observer возвращает место наблюдения как наблюдателя для астрономических расчётов
+func (s *ObservingSite) Observer() astro.Observer {
+	return astro.Observer{
+		Latitude:     s.Latitude,
+		Longitude:    s.Longitude,
+		Elevation:    s.Elevation,
+		HorizonLimit: s.HorizonLimit,
+	}
+}
+
+// Location возвращает часовой пояс места наблюдения; при неизвестном поясе — UTC
+func (s *ObservingSite) Location() *time.Location {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil || s.Timezone == "" {
+		return time.UTC
+	}
+	return loc
+}
+
+// FocalRatio возвращает светосилу телескопа (знаменатель f/N); 0, если параметры не заданы
+func (t *Telescope) FocalRatio() float64 {
+	if t.Aperture <= 0 {
+		return 0
+	}
+	return t.FocalLength / t.Aperture
+}
+
+// MagnitudeLimit возвращает предельную звёздную величину в зените: указанную в профиле
+// или оценку по апертуре m = 2.7 + 5·lg D (D в мм), если она не задана
+func (t *Telescope) MagnitudeLimit() float64 {
+	if t.LimitingMagnitude != 0 || t.Aperture <= 0 {
+		return t.LimitingMagnitude
+	}
+	return 2.7 + 5*math.Log10(t.Aperture)
+}
+
+// LimitingMagnitudeAt возвращает предельную звёздную величину телескопа заявки в направлении
+// на звезду в момент наблюдения: предел в зените минус дополнительная экстинкция k·(X−1)
+// относительно зенита. ok = false, если телескоп не выбран или звезда под горизонтом.
+func (o *TelescopeObservation) LimitingMagnitudeAt(star Star) (limit, airmass float64, ok bool) {
+	if o.Telescope == nil {
+		return 0, 0, false
+	}
+	airmass, ok = o.AirmassAt(star)
+	if !ok {
+		return 0, 0, false
+	}
+	return o.Telescope.MagnitudeLimit() - astro.Extinction(airmass-1, o.Observer().ExtinctionCoefficient()), airmass, true
+}
+
+// AirmassAt возвращает воздушную массу в направлении на звезду в момент наблюдения;
+// ok = false, если звезда под горизонтом
+func (o *TelescopeObservation) AirmassAt(star Star) (float64, bool) {
+	observedAt := o.ObservedAt()
+	ra, dec := star.ApparentPosition(observedAt)
+	hor := astro.EquatorialToHorizontal(ra, dec, o.Observer(), observedAt)
+	return astro.Airmass(hor.Altitude)
+}
+
+// ReadoutTime возвращает время считывания кадра камерой заявки, секунды; без камеры — 0
+func (o *TelescopeObservation) ReadoutTime() float64 {
+	if o.Camera == nil {
+		return 0
+	}
+	return o.Camera.ReadoutTime
+}
+
+// SetSite привязывает заявку к месту наблюдения и копирует его координаты в заявку
+func (o *TelescopeObservation) SetSite(site *ObservingSite) {
+	o.SiteID = &site.SiteID
+	o.Site = site
+	o.ObserverLatitude = site.Latitude
+	o.ObserverLongitude = site.Longitude
+}
+
+// Observer возвращает место наблюдения заявки. Координаты всегда берутся из заявки:
+// у черновиков они синхронизируются с местом из справочника, а сформированные и
+// завершённые заявки сохраняют координаты на момент оформления. Высота над уровнем
+// моря и ограничение по горизонту берутся из места, если оно загружено.
+func (o *TelescopeObservation) Observer() astro.Observer {
+	observer := astro.Observer{}
+	if o.Site != nil {
+		observer = o.Site.Observer()
+	}
+	observer.Latitude = o.ObserverLatitude
+	observer.Longitude = o.ObserverLongitude
+	return observer
+}
+
+// BeforeSave приводит момент наблюдения к UTC перед записью в БД
+func (o *TelescopeObservation) BeforeSave(tx *gorm.DB) error {
+	if o.ObservationDate != nil {
+		utc := o.ObservationDate.UTC()
+		o.ObservationDate = &utc
+	}
+	return nil
+}
+
+// ObservedAt возвращает момент наблюдения в UTC; если дата не указана — текущий момент
+func (o *TelescopeObservation) ObservedAt() time.Time {
+	if o.ObservationDate != nil {
+		return o.ObservationDate.UTC()
+	}
+	return time.Now().UTC()
+}
+
+// Location возвращает часовой пояс места наблюдения заявки; без места — UTC
+func (o *TelescopeObservation) Location() *time.Location {
+	if o.Site != nil {
+		return o.Site.Location()
+	}
+	return time.UTC
+}
+
+// FillObservationTime переводит момент наблюдения и плановое время звёзд в часовой пояс места
+// и рассчитывает для момента наблюдения юлианскую дату и местное звёздное время
+func (o *TelescopeObservation) FillObservationTime() {
+	observedAt := o.ObservedAt()
+	loc := o.Location()
+	local := observedAt.In(loc)
+	lst := astro.LocalSiderealTime(observedAt, o.Observer().Longitude)
+
+	if o.ObservationDate != nil {
+		o.ObservationDate = &local
+	}
+	for i := range o.TelescopeObservationStars {
+		s := &o.TelescopeObservationStars[i]
+		if s.PlannedStart != nil {
+			start := s.PlannedStart.In(loc)
+			s.PlannedStart = &start
+		}
+	}
+	o.ObservationTime = &ObservationTime{
+		UTC:                  observedAt,
+		Local:                local,
+		Timezone:             loc.String(),
+		UTCOffset:            local.Format("-07:00"),
+		JulianDate:           astro.JulianDate(observedAt),
+		LocalSiderealTime:    lst,
+		LocalSiderealTimeHMS: astro.FormatRA(lst * 15),
+	}
+}
+
+// FillVisibility рассчитывает восход, кульминацию и заход для каждой звезды заявки.
+// Моменты выдаются в часовом поясе места наблюдения.
+func (o *TelescopeObservation) FillVisibility() {
+	observer := o.Observer()
+	observedAt := o.ObservedAt()
+	loc := o.Location()
+
+	for i := range o.TelescopeObservationStars {
+		s := &o.TelescopeObservationStars[i]
+		ra, dec := s.Star.ApparentPosition(observedAt)
+		v := astro.RiseTransitSet(ra, dec, observer, observedAt).In(loc)
+		s.Visibility = &v
+	}
+}
+
+// FillSky рассчитывает положение Солнца и Луны, сумерки и фазу Луны на момент наблюдения,
+// а также расстояние от Луны до каждой звезды заявки
+func (o *TelescopeObservation) FillSky() {
+	observer := o.Observer()
+	observedAt := o.ObservedAt()
+
+	sky := astro.SkyAt(observer, observedAt)
+	sky.Night = sky.Night.In(o.Location())
+	o.Sky = &sky
+
+	for i := range o.TelescopeObservationStars {
+		s := &o.TelescopeObservationStars[i]
+		ra, dec := s.Star.ApparentPosition(observedAt)
+		separation := math.Round(sky.MoonSeparation(ra, dec)*100) / 100
+		s.MoonSeparation = &separation
+	}
+}
+
+// VisibilityWindow возвращает интервал от самого раннего восхода до самого позднего
+// захода звёзд заявки, ограниченный тёмным временем ночи наблюдения (astro.Night.Dark).
+// Если звёзды не восходят ночью, возвращается вся тёмная часть ночи.
+// Видимость звёзд должна быть рассчитана FillVisibility.
+func (o *TelescopeObservation) VisibilityWindow() (start, end time.Time) {
+	observedAt := o.ObservedAt()
+	earliest := observedAt.Add(-12 * time.Hour)
+	latest := observedAt.Add(12 * time.Hour)
+
+	found := false
+	for _, s := range o.TelescopeObservationStars {
+		v := s.Visibility
+		if v == nil || v.NeverRises {
+			continue
+		}
+
+		rise, set := earliest, latest
+		if v.Rise != nil && v.Set != nil {
+			rise, set = *v.Rise, *v.Set
+		}
+		if !found || rise.Before(start) {
+			start = rise
+		}
+		if !found || set.After(end) {
+			end = set
+		}
+		found = true
+	}
+
+	if !found {
+		// Ни одна звезда не восходит — событие занимает час от момента наблюдения
+		return observedAt, observedAt.Add(time.Hour)
+	}
+	night := astro.NightAt(o.Observer(), observedAt).Dark()
+	if start.Before(night.Start) {
+		start = night.Start
+	}
+	if end.After(night.End) {
+		end = night.End
+	}
+	if !end.After(start) {
+		return night.Start, night.End
+	}
+	return start, end
+}
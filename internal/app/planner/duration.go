@@ -0,0 +1,40 @@
+package planner
+
+import (
+	"Lab1/internal/app/astro"
+	"Lab1/internal/app/models"
+	"sort"
+)
+
+// EstimateDuration оценивает продолжительность наблюдений заявки: съёмку звёзд по их
+// последовательностям (без последовательности — как в расписании, по Quantity) и наведения
+// между звёздами в порядке order_number со скоростью телескопа заявки. Расстояния
+// наведения, как и в Plan, считаются по видимым положениям звёзд на момент наблюдения.
+func EstimateDuration(order *models.TelescopeObservation) models.ObservationDuration {
+	s := ForOrder(order, astro.Interval{})
+	readout := order.ReadoutTime()
+	observedAt := order.ObservedAt()
+
+	stars := append([]models.TelescopeObservationStar{}, order.TelescopeObservationStars...)
+	sort.SliceStable(stars, func(i, j int) bool { return stars[i].OrderNumber < stars[j].OrderNumber })
+
+	var d models.ObservationDuration
+	var prevRA, prevDec float64
+	for i, star := range stars {
+		if len(star.Exposures) > 0 {
+			exposure, readoutTotal := star.SequenceDuration(readout)
+			d.Exposure += exposure
+			d.Readout += readoutTotal
+		} else {
+			d.Exposure += s.TargetDuration(star).Seconds()
+		}
+		ra, dec := star.Star.ApparentPosition(observedAt)
+		if i > 0 {
+			slew := astro.AngularSeparation(prevRA, prevDec, ra, dec)
+			d.Overhead += s.slewTime(slew, true).Seconds()
+		}
+		prevRA, prevDec = ra, dec
+	}
+	d.Total = d.Exposure + d.Readout + d.Overhead
+	return d
+}
@@ -0,0 +1,198 @@
+package planner
+
+import (
+	"Lab1/internal/app/astro"
+	"Lab1/internal/app/models"
+	"math"
+	"time"
+)
+
+// Параметры расписания по умолчанию, если у заявки не указан телескоп
+const (
+	DefaultMinAltitude    = 20.0             // минимальная высота наблюдения, градусы
+	DefaultSlewRate       = 2.0              // скорость наведения, градусов в секунду
+	DefaultSettleTime     = 30 * time.Second // успокоение монтировки после наведения
+	DefaultTargetDuration = 10 * time.Minute // наблюдение одной звезды при Quantity = 1
+)
+
+// Шаг ожидания, когда ни одна звезда не доступна
+const waitStep = 10 * time.Minute
+
+// Веса слагаемых стоимости выбора следующей звезды
+const (
+	slewWeight     = 1.0 // наведение через полнеба
+	altitudeWeight = 1.0 // звезда у горизонта вместо зенита
+	urgencyWeight  = 1.0 // звезда будет доступна ещё 12 часов
+)
+
+// ScheduledTarget — звезда заявки в расписании
+type ScheduledTarget struct {
+	StarID      int
+	OrderNumber int
+	Start       time.Time // начало наблюдения, после наведения
+	End         time.Time
+	Altitude    float64 // высота в начале наблюдения, градусы
+	Slew        float64 // угол наведения от предыдущей звезды, градусы
+}
+
+// Schedule — расписание наблюдений заявки
+type Schedule struct {
+	Window      astro.Interval
+	Targets     []ScheduledTarget
+	Unscheduled []int // звёзды, не поместившиеся в промежуток или не поднимающиеся достаточно высоко
+}
+
+// Scheduler составляет расписание жадно: на каждом шаге выбирается доступная звезда
+// с наименьшей стоимостью, складывающейся из угла наведения от текущей звезды,
+// зенитного расстояния и оставшегося времени доступности (раньше — те, что скоро зайдут)
+type Scheduler struct {
+	Observer    astro.Observer
+	Window      astro.Interval
+	MinAltitude float64
+	SlewRate    float64
+	SettleTime  time.Duration
+
+	// Длительность наблюдения звезды заявки без учёта наведения
+	TargetDuration func(models.TelescopeObservationStar) time.Duration
+}
+
+// NewScheduler создаёт планировщик с параметрами по умолчанию
+func NewScheduler(obs astro.Observer, window astro.Interval) *Scheduler {
+	return &Scheduler{
+		Observer:       obs,
+		Window:         window,
+		MinAltitude:    math.Max(DefaultMinAltitude, obs.HorizonLimit),
+		SlewRate:       DefaultSlewRate,
+		SettleTime:     DefaultSettleTime,
+		TargetDuration: defaultTargetDuration,
+	}
+}
+
+// defaultTargetDuration — DefaultTargetDuration на каждое наблюдение Quantity
+func defaultTargetDuration(s models.TelescopeObservationStar) time.Duration {
+	return time.Duration(max(s.Quantity, 1)) * DefaultTargetDuration
+}
+
+// ForOrder создаёт планировщик для места наблюдения, телескопа и камеры заявки.
+// Звезда с последовательностью съёмки наблюдается столько, сколько занимают её кадры со считыванием.
+func ForOrder(order *models.TelescopeObservation, window astro.Interval) *Scheduler {
+	s := NewScheduler(order.Observer(), window)
+	if order.Telescope != nil && order.Telescope.SlewRate > 0 {
+		s.SlewRate = order.Telescope.SlewRate
+	}
+	readout := order.ReadoutTime()
+	s.TargetDuration = func(star models.TelescopeObservationStar) time.Duration {
+		if len(star.Exposures) == 0 {
+			return defaultTargetDuration(star)
+		}
+		exposure, readoutTotal := star.SequenceDuration(readout)
+		return seconds(exposure + readoutTotal)
+	}
+	return s
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+type candidate struct {
+	star     models.TelescopeObservationStar
+	ra, dec  float64
+	duration time.Duration
+}
+
+// Plan составляет расписание для звёзд заявки
+func (s *Scheduler) Plan(stars []models.TelescopeObservationStar) Schedule {
+	schedule := Schedule{Window: s.Window, Targets: []ScheduledTarget{}, Unscheduled: []int{}}
+
+	epoch := s.Window.Start.Add(s.Window.Duration() / 2)
+	pending := make([]candidate, 0, len(stars))
+	for _, star := range stars {
+		ra, dec := star.Star.ApparentPosition(epoch)
+		pending = append(pending, candidate{star: star, ra: ra, dec: dec, duration: s.TargetDuration(star)})
+	}
+
+	now := s.Window.Start
+	var current *candidate
+	for len(pending) > 0 && now.Before(s.Window.End) {
+		best, bestCost := -1, math.Inf(1)
+		var bestSlew, bestAltitude float64
+		var bestStart time.Time
+
+		for i := range pending {
+			c := &pending[i]
+			slew := 0.0
+			if current != nil {
+				slew = astro.AngularSeparation(current.ra, current.dec, c.ra, c.dec)
+			}
+			start := now.Add(s.slewTime(slew, current != nil))
+			end := start.Add(c.duration)
+			if end.After(s.Window.End) {
+				continue
+			}
+
+			altitude := s.altitude(c, start)
+			if altitude < s.MinAltitude || s.altitude(c, end) < s.MinAltitude {
+				continue
+			}
+
+			cost := slewWeight*slew/180 +
+				altitudeWeight*(90-altitude)/90 +
+				urgencyWeight*s.timeLeft(c, end).Hours()/12
+			if cost < bestCost {
+				best, bestCost = i, cost
+				bestSlew, bestAltitude, bestStart = slew, altitude, start
+			}
+		}
+
+		if best < 0 {
+			// Ни одна звезда сейчас недоступна — ждём
+			now = now.Add(waitStep)
+			continue
+		}
+
+		chosen := pending[best]
+		end := bestStart.Add(chosen.duration)
+		schedule.Targets = append(schedule.Targets, ScheduledTarget{
+			StarID:      chosen.star.StarID,
+			OrderNumber: len(schedule.Targets) + 1,
+			Start:       bestStart,
+			End:         end,
+			Altitude:    math.Round(bestAltitude*100) / 100,
+			Slew:        math.Round(bestSlew*100) / 100,
+		})
+
+		pending = append(pending[:best], pending[best+1:]...)
+		current = &chosen
+		now = end
+	}
+
+	for _, c := range pending {
+		schedule.Unscheduled = append(schedule.Unscheduled, c.star.StarID)
+	}
+	return schedule
+}
+
+// slewTime возвращает время наведения на угол slew градусов с успокоением монтировки
+func (s *Scheduler) slewTime(slew float64, moved bool) time.Duration {
+	if !moved {
+		return 0
+	}
+	return time.Duration(slew/s.SlewRate*float64(time.Second)) + s.SettleTime
+}
+
+func (s *Scheduler) altitude(c *candidate, t time.Time) float64 {
+	return astro.EquatorialToHorizontal(c.ra, c.dec, s.Observer, t).Altitude
+}
+
+// timeLeft возвращает, сколько звезда ещё пробудет выше минимальной высоты после момента t
+// (в пределах промежутка расписания)
+func (s *Scheduler) timeLeft(c *candidate, t time.Time) time.Duration {
+	altitude := func(t time.Time) float64 { return s.altitude(c, t) }
+	for _, crossing := range astro.FindCrossings(altitude, t, s.Window.End, s.MinAltitude) {
+		if !crossing.Rising {
+			return crossing.Time.Sub(t)
+		}
+	}
+	return s.Window.End.Sub(t)
+}
@@ -0,0 +1,114 @@
+package planner
+
+import (
+	"Lab1/internal/app/astro"
+	"Lab1/internal/app/models"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// VisibleStar — звезда, поднимающаяся выше заданной высоты в течение промежутка наблюдения
+type VisibleStar struct {
+	Star         models.Star
+	PeakAltitude float64   // наибольшая высота за промежуток, градусы
+	PeakTime     time.Time // момент наибольшей высоты
+}
+
+// Tonight возвращает промежуток наблюдений ночи, которой принадлежит момент t
+// (см. astro.Night.Dark)
+func Tonight(obs astro.Observer, t time.Time) astro.Interval {
+	return astro.NightAt(obs, t).Dark()
+}
+
+// ParseWindow строит промежуток наблюдения из строк from и to (см. ParseTime).
+// Пустой from заменяется началом ночи, пустой to — её концом: берётся ночь,
+// которой принадлежит from, а без него — момент now.
+func ParseWindow(obs astro.Observer, loc *time.Location, fromText, toText string, now time.Time) (astro.Interval, error) {
+	window := Tonight(obs, now)
+
+	if fromText != "" {
+		from, err := ParseTime(fromText, loc)
+		if err != nil {
+			return astro.Interval{}, fmt.Errorf("from: %w", err)
+		}
+		window = Tonight(obs, from)
+		window.Start = from
+	}
+	if toText != "" {
+		to, err := ParseTime(toText, loc)
+		if err != nil {
+			return astro.Interval{}, fmt.Errorf("to: %w", err)
+		}
+		window.End = to
+	}
+
+	if !window.End.After(window.Start) {
+		return astro.Interval{}, fmt.Errorf("конец промежутка должен быть позже начала")
+	}
+	return window, nil
+}
+
+// Visibility отбирает звёзды, поднимающиеся в промежутке window выше minAltitude
+type Visibility struct {
+	Observer    astro.Observer
+	Window      astro.Interval
+	MinAltitude float64
+
+	// Положения звёзд берутся на середину промежутка: за ночь они практически не меняются
+	epoch time.Time
+	stars []VisibleStar
+}
+
+// NewVisibility создаёт фильтр видимости. Высота не может быть ниже ограничения по горизонту места.
+func NewVisibility(obs astro.Observer, window astro.Interval, minAltitude float64) *Visibility {
+	if obs.HorizonLimit > minAltitude {
+		minAltitude = obs.HorizonLimit
+	}
+	return &Visibility{
+		Observer:    obs,
+		Window:      window,
+		MinAltitude: minAltitude,
+		epoch:       window.Start.Add(window.Duration() / 2),
+	}
+}
+
+// Add проверяет звезду и запоминает её, если она видна. Возвращает true для видимой звезды.
+func (v *Visibility) Add(star models.Star) bool {
+	ra, dec := star.ApparentPosition(v.epoch)
+	altitude, at := astro.PeakAltitude(ra, dec, v.Observer, v.Window.Start, v.Window.End)
+	if altitude < v.MinAltitude {
+		return false
+	}
+	v.stars = append(v.stars, VisibleStar{Star: star, PeakAltitude: altitude, PeakTime: at})
+	return true
+}
+
+// Stars возвращает видимые звёзды по убыванию наибольшей высоты
+func (v *Visibility) Stars() []VisibleStar {
+	sort.SliceStable(v.stars, func(i, j int) bool { return v.stars[i].PeakAltitude > v.stars[j].PeakAltitude })
+	return v.stars
+}
+
+// Форматы времени без смещения от UTC
+var localLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+// ParseTime разбирает момент времени. Время со смещением (RFC 3339) берётся как есть,
+// время без смещения — как местное в часовом поясе loc с учётом перехода на летнее время.
+// Результат — в UTC.
+func ParseTime(text string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, text); err == nil {
+		return t.UTC(), nil
+	}
+	for _, layout := range localLayouts {
+		if t, err := time.ParseInLocation(layout, text, loc); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("некорректное время %q, ожидается RFC 3339 или ГГГГ-ММ-ДДTЧЧ:ММ", text)
+}
@@ -0,0 +1,36 @@
+package repository
+
+import "Lab1/internal/app/models"
+
+func (r *Repository) GetCameras() ([]models.Camera, error) {
+	var cameras []models.Camera
+	err := r.DB.Order("name").Find(&cameras).Error
+	return cameras, err
+}
+
+func (r *Repository) GetCameraByID(id int) (*models.Camera, error) {
+	var camera models.Camera
+	if err := r.DB.First(&camera, id).Error; err != nil {
+		return nil, err
+	}
+	return &camera, nil
+}
+
+func (r *Repository) CreateCamera(camera *models.Camera) error {
+	return r.DB.Create(camera).Error
+}
+
+func (r *Repository) UpdateCameraFields(id int, updates map[string]interface{}) error {
+	return r.DB.Model(&models.Camera{}).Where("camera_id = ?", id).Updates(updates).Error
+}
+
+func (r *Repository) DeleteCamera(id int) error {
+	return r.DB.Delete(&models.Camera{}, id).Error
+}
+
+// CountCameraOrders возвращает число заявок, ссылающихся на камеру
+func (r *Repository) CountCameraOrders(id int) (int64, error) {
+	var count int64
+	err := r.DB.Model(&models.TelescopeObservation{}).Where("camera_id = ?", id).Count(&count).Error
+	return count, err
+}
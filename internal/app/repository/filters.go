@@ -0,0 +1,36 @@
+package repository
+
+import "Lab1/internal/app/models"
+
+func (r *Repository) GetFilters() ([]models.Filter, error) {
+	var filters []models.Filter
+	err := r.DB.Order("wavelength, name").Find(&filters).Error
+	return filters, err
+}
+
+func (r *Repository) GetFilterByID(id int) (*models.Filter, error) {
+	var filter models.Filter
+	if err := r.DB.First(&filter, id).Error; err != nil {
+		return nil, err
+	}
+	return &filter, nil
+}
+
+func (r *Repository) CreateFilter(filter *models.Filter) error {
+	return r.DB.Create(filter).Error
+}
+
+func (r *Repository) UpdateFilterFields(id int, updates map[string]interface{}) error {
+	return r.DB.Model(&models.Filter{}).Where("filter_id = ?", id).Updates(updates).Error
+}
+
+func (r *Repository) DeleteFilter(id int) error {
+	return r.DB.Delete(&models.Filter{}, id).Error
+}
+
+// CountFilterExposures возвращает число шагов последовательностей съёмки с фильтром
+func (r *Repository) CountFilterExposures(id int) (int64, error) {
+	var count int64
+	err := r.DB.Model(&models.ObservationExposure{}).Where("filter_id = ?", id).Count(&count).Error
+	return count, err
+}
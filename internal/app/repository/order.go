@@ -2,6 +2,7 @@ package repository
 
 import (
 	"Lab1/internal/app/models"
+	"Lab1/internal/app/planner"
 	"errors"
 	"time"
 
@@ -31,9 +32,15 @@ func (r *Repository) GetOrder(id int) (*models.TelescopeObservation, error) {
 	var order models.TelescopeObservation
 
 	err := r.DB.
+		Preload("TelescopeObservationStars", ByOrderNumber).
 		Preload("TelescopeObservationStars.Star").
+		Preload("TelescopeObservationStars.Exposures", ByPosition).
+		Preload("TelescopeObservationStars.Exposures.Filter").
 		Preload("Creator").
 		Preload("Moderator").
+		Preload("Site").
+		Preload("Telescope").
+		Preload("Camera").
 		Where("telescope_observation_id = ? AND status <> ?", id, "удалён").
 		First(&order).Error
 
@@ -43,6 +50,29 @@ func (r *Repository) GetOrder(id int) (*models.TelescopeObservation, error) {
 	return &order, nil
 }
 
+// ByOrderNumber упорядочивает звёзды заявки по порядку наблюдения при загрузке
+func ByOrderNumber(db *gorm.DB) *gorm.DB {
+	return db.Order("order_number")
+}
+
+// ByPosition упорядочивает шаги последовательности съёмки при загрузке
+func ByPosition(db *gorm.DB) *gorm.DB {
+	return db.Order("position")
+}
+
+// Заявки пользователя в указанных статусах вместе со звёздами
+func (r *Repository) GetUserOrdersByStatuses(userID int, statuses []string) ([]models.TelescopeObservation, error) {
+	var orders []models.TelescopeObservation
+	err := r.DB.
+		Preload("TelescopeObservationStars.Star").
+		Preload("Site").
+		Preload("Telescope").
+		Where("creator_id = ? AND status IN ?", userID, statuses).
+		Order("observation_date").
+		Find(&orders).Error
+	return orders, err
+}
+
 // Получение корзин по статусу
 func (r *Repository) GetOrdersByStatus(status string) ([]models.TelescopeObservation, error) {
 	var orders []models.TelescopeObservation
@@ -74,7 +104,7 @@ func (r *Repository) GetOrCreateDraftOrder(userID int) (*models.TelescopeObserva
 
 	err := r.DB.Where("creator_id = ? AND status = ?", userID, "черновик").First(&order).Error
 	if err == gorm.ErrRecordNotFound {
-		now := time.Now()
+		now := time.Now().UTC()
 		order = models.TelescopeObservation{
 			CreatorID:         userID,
 			Status:            "черновик",
@@ -83,6 +113,16 @@ func (r *Repository) GetOrCreateDraftOrder(userID int) (*models.TelescopeObserva
 			ObserverLatitude:  0.0,
 			ObserverLongitude: 0.0,
 		}
+
+		// Новый черновик привязывается к месту наблюдения по умолчанию
+		site, err := r.GetDefaultSite()
+		if err != nil {
+			return nil, err
+		}
+		if site != nil {
+			order.SetSite(site)
+		}
+
 		if err := r.DB.Create(&order).Error; err != nil {
 			return nil, err
 		}
@@ -131,11 +171,15 @@ func (r *Repository) AddStarToOrder(orderID, starID int) error {
 	}
 
 	if errors.Is(err, gorm.ErrRecordNotFound) {
-		// Нет такой — добавляем новую
+		// Нет такой — добавляем новую в конец очереди наблюдений
+		orderNumber, err := r.NextOrderNumber(orderID)
+		if err != nil {
+			return err
+		}
 		link := models.TelescopeObservationStar{
 			TelescopeObservationID: orderID,
 			StarID:                 starID,
-			OrderNumber:            1,
+			OrderNumber:            orderNumber,
 			Quantity:               1,
 		}
 		if err := r.DB.Create(&link).Error; err != nil {
@@ -147,15 +191,85 @@ func (r *Repository) AddStarToOrder(orderID, starID int) error {
 	return err
 }
 
-func (r *Repository) UpdateObservationStarResult(observationID, starID int, result float64) error {
+// SaveSchedule записывает порядок наблюдения и плановое время начала звёзд заявки.
+// Звёзды, не вошедшие в расписание, ставятся в конец очереди без планового времени.
+func (r *Repository) SaveSchedule(orderID int, schedule planner.Schedule) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		update := func(starID, orderNumber int, start *time.Time) error {
+			return tx.Model(&models.TelescopeObservationStar{}).
+				Where("telescope_observation_id = ? AND star_id = ?", orderID, starID).
+				Updates(map[string]interface{}{
+					"order_number":  orderNumber,
+					"planned_start": start,
+				}).Error
+		}
+
+		for _, target := range schedule.Targets {
+			start := target.Start.UTC()
+			if err := update(target.StarID, target.OrderNumber, &start); err != nil {
+				return err
+			}
+		}
+		for i, starID := range schedule.Unscheduled {
+			if err := update(starID, len(schedule.Targets)+i+1, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// NextOrderNumber возвращает порядковый номер для звезды, добавляемой в конец заявки
+func (r *Repository) NextOrderNumber(orderID int) (int, error) {
+	var max int
+	err := r.DB.Model(&models.TelescopeObservationStar{}).
+		Select("COALESCE(MAX(order_number), 0)").
+		Where("telescope_observation_id = ?", orderID).
+		Scan(&max).Error
+	return max + 1, err
+}
+
+func (r *Repository) UpdateObservationStarResult(observationID, starID int, result, airmass, extinction *float64) error {
 	return r.DB.Model(&models.TelescopeObservationStar{}).
 		Where("telescope_observation_id = ? AND star_id = ?", observationID, starID).
-		Update("result_value", result).Error
+		Updates(map[string]interface{}{
+			"result_value": result,
+			"airmass":      airmass,
+			"extinction":   extinction,
+		}).Error
 }
 
-// Удалить запись м-м по observation_id + star_id
+// Удалить запись м-м по observation_id + star_id вместе с последовательностью съёмки звезды
 func (r *Repository) DeleteObservationStar(observationID, starID int) error {
-	return r.DB.Exec("DELETE FROM telescope_observation_stars WHERE telescope_observation_id = ? AND star_id = ?", observationID, starID).Error
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("telescope_observation_id = ? AND star_id = ?", observationID, starID).
+			Delete(&models.ObservationExposure{}).Error; err != nil {
+			return err
+		}
+		return tx.Exec("DELETE FROM telescope_observation_stars WHERE telescope_observation_id = ? AND star_id = ?", observationID, starID).Error
+	})
+}
+
+// SaveSequence заменяет последовательность съёмки звезды заявки шагами steps;
+// пустой steps удаляет последовательность
+func (r *Repository) SaveSequence(observationID, starID int, steps []models.ObservationExposure) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("telescope_observation_id = ? AND star_id = ?", observationID, starID).
+			Delete(&models.ObservationExposure{}).Error; err != nil {
+			return err
+		}
+
+		if len(steps) == 0 {
+			return nil
+		}
+		for i := range steps {
+			steps[i].ObservationExposureID = 0
+			steps[i].TelescopeObservationID = observationID
+			steps[i].StarID = starID
+			steps[i].Position = i + 1
+		}
+		return tx.Omit("Filter").Create(&steps).Error
+	})
 }
 
 // Обновить поля записи м-м (quantity, order_number, result_value, observer_latitude, observer_longitude)
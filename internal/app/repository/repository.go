@@ -20,6 +20,26 @@ func NewRepository(dsn string) (*Repository, error) {
 	return &Repository{DB: db}, nil
 }
 
+// Migrate добавляет в таблицы недостающие столбцы моделей
+// и заполняет пиксели HEALPix у звёзд, где их ещё нет
+func (r *Repository) Migrate() error {
+	err := r.DB.AutoMigrate(
+		&models.User{},
+		&models.Star{},
+		&models.ObservingSite{},
+		&models.Telescope{},
+		&models.Camera{},
+		&models.Filter{},
+		&models.TelescopeObservation{},
+		&models.TelescopeObservationStar{},
+		&models.ObservationExposure{},
+	)
+	if err != nil {
+		return err
+	}
+	return r.FillMissingHEALPix()
+}
+
 func NewRepositoryFromDB(db *gorm.DB) *Repository {
 	return &Repository{DB: db}
 }
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"Lab1/internal/app/models"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+func (r *Repository) GetSites() ([]models.ObservingSite, error) {
+	var sites []models.ObservingSite
+	err := r.DB.Order("name").Find(&sites).Error
+	return sites, err
+}
+
+func (r *Repository) GetSiteByID(id int) (*models.ObservingSite, error) {
+	var site models.ObservingSite
+	if err := r.DB.First(&site, id).Error; err != nil {
+		return nil, err
+	}
+	return &site, nil
+}
+
+// GetDefaultSite возвращает место по умолчанию; nil, если оно не назначено
+func (r *Repository) GetDefaultSite() (*models.ObservingSite, error) {
+	var site models.ObservingSite
+	err := r.DB.Where("is_default = ?", true).First(&site).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &site, nil
+}
+
+// CreateSite создаёт место; если оно помечено местом по умолчанию, пометка снимается с остальных
+func (r *Repository) CreateSite(site *models.ObservingSite) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if site.IsDefault {
+			if err := resetDefaultSite(tx); err != nil {
+				return err
+			}
+		}
+		return tx.Create(site).Error
+	})
+}
+
+// UpdateSiteFields обновляет поля места. Координаты заявок, привязанных к месту,
+// обновляются вместе с ним.
+func (r *Repository) UpdateSiteFields(id int, updates map[string]interface{}) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if isDefault, _ := updates["is_default"].(bool); isDefault {
+			if err := resetDefaultSite(tx); err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&models.ObservingSite{}).Where("site_id = ?", id).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		var site models.ObservingSite
+		if err := tx.First(&site, id).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.TelescopeObservation{}).
+			Where("site_id = ? AND status = ?", id, "черновик").
+			Updates(map[string]interface{}{
+				"observer_latitude":  site.Latitude,
+				"observer_longitude": site.Longitude,
+			}).Error
+	})
+}
+
+func (r *Repository) DeleteSite(id int) error {
+	return r.DB.Delete(&models.ObservingSite{}, id).Error
+}
+
+// CountSiteOrders возвращает число заявок, ссылающихся на место
+func (r *Repository) CountSiteOrders(id int) (int64, error) {
+	var count int64
+	err := r.DB.Model(&models.TelescopeObservation{}).Where("site_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+func resetDefaultSite(tx *gorm.DB) error {
+	return tx.Model(&models.ObservingSite{}).Where("is_default = ?", true).Update("is_default", false).Error
+}
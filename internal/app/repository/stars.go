@@ -1,6 +1,13 @@
 package repository
 
-import "Lab1/internal/app/models"
+import (
+	"Lab1/internal/app/astro"
+	"Lab1/internal/app/models"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
 
 func (r *Repository) GetStars() ([]models.Star, error) {
 	var stars []models.Star
@@ -21,6 +28,54 @@ func (r *Repository) GetStarByID(id int) (*models.Star, error) {
 	return &star, nil
 }
 
+func (r *Repository) GetStarByDesignation(designation string) (*models.Star, error) {
+	var star models.Star
+	if err := r.DB.Where("designation = ?", designation).First(&star).Error; err != nil {
+		return nil, err
+	}
+	return &star, nil
+}
+
+func (r *Repository) CreateStar(star *models.Star) error {
+	return r.DB.Create(star).Error
+}
+
+// UpdateStarFields обновляет столбцы звезды; при изменении положения пересчитывает пиксель HEALPix
+func (r *Repository) UpdateStarFields(id int, updates map[string]interface{}) error {
+	if err := r.DB.Model(&models.Star{}).Where("star_id = ?", id).Updates(updates).Error; err != nil {
+		return err
+	}
+	for _, col := range models.StarPositionColumns {
+		if _, ok := updates[col]; ok {
+			return r.updateStarHEALPix(id)
+		}
+	}
+	return nil
+}
+
+func (r *Repository) updateStarHEALPix(id int) error {
+	var star models.Star
+	if err := r.DB.First(&star, id).Error; err != nil {
+		return err
+	}
+	star.FillHEALPix()
+	return r.DB.Model(&models.Star{}).Where("star_id = ?", id).Update("healpix", star.HEALPix).Error
+}
+
+// FillMissingHEALPix рассчитывает пиксели HEALPix звёзд, добавленных до появления столбца
+func (r *Repository) FillMissingHEALPix() error {
+	var batch []models.Star
+	return r.DB.Where("healpix IS NULL").FindInBatches(&batch, 500, func(tx *gorm.DB, _ int) error {
+		for _, star := range batch {
+			star.FillHEALPix()
+			if err := r.DB.Model(&models.Star{}).Where("star_id = ?", star.StarID).Update("healpix", star.HEALPix).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+}
+
 func (r *Repository) SearchStars(query string) ([]models.Star, error) {
 	var stars []models.Star
 	err := r.DB.
@@ -28,3 +83,59 @@ func (r *Repository) SearchStars(query string) ([]models.Star, error) {
 		Find(&stars).Error
 	return stars, err
 }
+
+func (r *Repository) GetStarsByIDs(ids []int) ([]models.Star, error) {
+	var stars []models.Star
+	err := r.DB.Where("star_id IN ?", ids).Find(&stars).Error
+	return stars, err
+}
+
+// GetStarsNear возвращает звёзды в пределах radius градусов от точки (ra, dec) ICRS J2000,
+// отсортированные по возрастанию углового расстояния.
+// Кандидаты отбираются по индексу на пикселях HEALPix, покрывающих круг, точная
+// проверка идёт по положению звезды, перенесённому на J2000.
+func (r *Repository) GetStarsNear(ra, dec, radius float64) ([]models.Star, error) {
+	ranges := astro.HEALPixDisc(models.StarHEALPixOrder, ra, dec, radius)
+	if len(ranges) == 0 {
+		return []models.Star{}, nil
+	}
+
+	conditions := make([]string, len(ranges))
+	args := make([]interface{}, 0, 2*len(ranges))
+	for i, pr := range ranges {
+		conditions[i] = "healpix BETWEEN ? AND ?"
+		args = append(args, pr.First, pr.Last)
+	}
+
+	var candidates []models.Star
+	if err := r.DB.Where(strings.Join(conditions, " OR "), args...).Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+
+	stars := make([]models.Star, 0, len(candidates))
+	separations := map[int]float64{}
+	for _, star := range candidates {
+		sra, sdec := star.J2000Position()
+		if d := astro.AngularSeparation(ra, dec, sra, sdec); d <= radius {
+			separations[star.StarID] = d
+			stars = append(stars, star)
+		}
+	}
+	sort.SliceStable(stars, func(i, j int) bool {
+		return separations[stars[i].StarID] < separations[stars[j].StarID]
+	})
+	return stars, nil
+}
+
+// EachStar передаёт звёзды каталога в fn по одной, читая их из БД пачками
+func (r *Repository) EachStar(fn func(models.Star) error) error {
+	var batch []models.Star
+	return r.DB.Order("star_id").FindInBatches(&batch, 500, func(tx *gorm.DB, _ int) error {
+		for _, star := range batch {
+			if err := fn(star); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+}
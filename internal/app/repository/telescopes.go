@@ -0,0 +1,36 @@
+package repository
+
+import "Lab1/internal/app/models"
+
+func (r *Repository) GetTelescopes() ([]models.Telescope, error) {
+	var telescopes []models.Telescope
+	err := r.DB.Order("name").Find(&telescopes).Error
+	return telescopes, err
+}
+
+func (r *Repository) GetTelescopeByID(id int) (*models.Telescope, error) {
+	var telescope models.Telescope
+	if err := r.DB.First(&telescope, id).Error; err != nil {
+		return nil, err
+	}
+	return &telescope, nil
+}
+
+func (r *Repository) CreateTelescope(telescope *models.Telescope) error {
+	return r.DB.Create(telescope).Error
+}
+
+func (r *Repository) UpdateTelescopeFields(id int, updates map[string]interface{}) error {
+	return r.DB.Model(&models.Telescope{}).Where("telescope_id = ?", id).Updates(updates).Error
+}
+
+func (r *Repository) DeleteTelescope(id int) error {
+	return r.DB.Delete(&models.Telescope{}, id).Error
+}
+
+// CountTelescopeOrders возвращает число заявок, ссылающихся на телескоп
+func (r *Repository) CountTelescopeOrders(id int) (int64, error) {
+	var count int64
+	err := r.DB.Model(&models.TelescopeObservation{}).Where("telescope_id = ?", id).Count(&count).Error
+	return count, err
+}
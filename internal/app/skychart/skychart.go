@@ -0,0 +1,108 @@
+package skychart
+
+import (
+	"Lab1/internal/app/astro"
+	"Lab1/internal/app/models"
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"time"
+)
+
+const (
+	size   = 600.0         // ширина и высота рисунка, пиксели
+	center = size / 2      // зенит
+	radius = size/2 - 40   // радиус круга горизонта
+	night  = 6 * time.Hour // трек строится на столько до и после момента наблюдения
+	step   = 10 * time.Minute
+)
+
+// Цвета треков звёзд по порядку
+var palette = []string{"#FFD479", "#7FDBFF", "#FF6F91", "#9BE564", "#C39BD3", "#F5A65B", "#4FC3F7", "#E57373"}
+
+// project переводит высоту и азимут (градусы) в точку полярной карты:
+// зенит в центре, горизонт по краю, север сверху, восток слева — как при взгляде на небо
+func project(alt, az float64) (x, y float64) {
+	r := radius * (90 - alt) / 90
+	a := az * math.Pi / 180
+	return center - r*math.Sin(a), center - r*math.Cos(a)
+}
+
+// Render рисует полярную карту неба в горизонтальных координатах для места и даты
+// наблюдения заявки: горизонт, стороны света и треки звёзд в течение ночи
+func Render(w io.Writer, order *models.TelescopeObservation) error {
+	b := bufio.NewWriter(w)
+	observer := order.Observer()
+	observedAt := order.ObservedAt()
+
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f" font-family="sans-serif">`+"\n", size, size, size, size)
+	fmt.Fprintf(b, `<rect width="100%%" height="100%%" fill="#0B1020"/>`+"\n")
+
+	// Горизонт и круги равных высот через 30°
+	fmt.Fprintf(b, `<circle cx="%.1f" cy="%.1f" r="%.1f" fill="#121A33" stroke="#C8C8C8" stroke-width="2"/>`+"\n", center, center, radius)
+	for _, alt := range []float64{30, 60} {
+		fmt.Fprintf(b, `<circle cx="%.1f" cy="%.1f" r="%.1f" fill="none" stroke="#3A4670" stroke-dasharray="4 4"/>`+"\n", center, center, radius*(90-alt)/90)
+		fmt.Fprintf(b, `<text x="%.1f" y="%.1f" fill="#6B7AA8" font-size="11">%.0f°</text>`+"\n", center+3, center-radius*(90-alt)/90-3, alt)
+	}
+	fmt.Fprintf(b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#3A4670"/>`+"\n", center, center-radius, center, center+radius)
+	fmt.Fprintf(b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#3A4670"/>`+"\n", center-radius, center, center+radius, center)
+
+	// Стороны света
+	for _, c := range []struct {
+		label string
+		az    float64
+	}{{"С", 0}, {"В", 90}, {"Ю", 180}, {"З", 270}} {
+		x, y := project(-8, c.az)
+		fmt.Fprintf(b, `<text x="%.1f" y="%.1f" fill="#EFEFEF" font-size="18" text-anchor="middle" dominant-baseline="middle">%s</text>`+"\n", x, y, c.label)
+	}
+
+	for i, s := range order.TelescopeObservationStars {
+		color := palette[i%len(palette)]
+		renderTrack(b, s.Star, observer, observedAt, color)
+	}
+
+	local := observedAt.In(order.Location())
+	fmt.Fprintf(b, `<text x="10" y="%.0f" fill="#8A96BF" font-size="12">%s %s, φ=%.4f°, λ=%.4f°</text>`+"\n",
+		size-10, local.Format("02.01.2006 15:04"), html.EscapeString(local.Location().String()), observer.Latitude, observer.Longitude)
+	fmt.Fprintln(b, `</svg>`)
+
+	return b.Flush()
+}
+
+// renderTrack рисует путь звезды над горизонтом и её положение в момент наблюдения
+func renderTrack(b *bufio.Writer, star models.Star, observer astro.Observer, observedAt time.Time, color string) {
+	var segment []string
+	flush := func() {
+		if len(segment) > 1 {
+			fmt.Fprintf(b, `<polyline fill="none" stroke="%s" stroke-width="1.5" stroke-opacity="0.8" points="`, color)
+			for _, p := range segment {
+				fmt.Fprint(b, p, " ")
+			}
+			fmt.Fprintln(b, `"/>`)
+		}
+		segment = segment[:0]
+	}
+
+	for t := observedAt.Add(-night); !t.After(observedAt.Add(night)); t = t.Add(step) {
+		ra, dec := star.ApparentPosition(t)
+		hor := astro.EquatorialToHorizontal(ra, dec, observer, t)
+		if hor.Altitude < 0 {
+			flush()
+			continue
+		}
+		x, y := project(hor.Altitude, hor.Azimuth)
+		segment = append(segment, fmt.Sprintf("%.1f,%.1f", x, y))
+	}
+	flush()
+
+	ra, dec := star.ApparentPosition(observedAt)
+	hor := astro.EquatorialToHorizontal(ra, dec, observer, observedAt)
+	if hor.Altitude < 0 {
+		return
+	}
+	x, y := project(hor.Altitude, hor.Azimuth)
+	fmt.Fprintf(b, `<circle cx="%.1f" cy="%.1f" r="5" fill="%s"/>`+"\n", x, y, color)
+	fmt.Fprintf(b, `<text x="%.1f" y="%.1f" fill="%s" font-size="13">%s</text>`+"\n", x+8, y-8, color, html.EscapeString(star.StarName))
+}